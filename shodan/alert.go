@@ -12,6 +12,7 @@ const (
 	alertInfoPath      = "/shodan/alert/%s/info"
 	alertDeletePath    = "/shodan/alert/%s"
 	alertCreatePath    = "/shodan/alert"
+	alertUpdatePath    = "/shodan/alert/%s"
 	alertNotifier      = "/shodan/alert/%s/notifier/%s"
 )
 
@@ -31,8 +32,7 @@ type Alert struct {
 	Size       int           `json:"size"`
 	Filters    *AlertFilters `json:"filters"`
 	Notifiers  []*Notifier   `json:"notifiers"`
-	// not documented for now :-(
-	Triggers map[string]interface{} `json:"triggers"`
+	Triggers   AlertTriggers `json:"triggers"`
 }
 
 type alertCreateRequest struct {
@@ -41,6 +41,11 @@ type alertCreateRequest struct {
 	Filters *AlertFilters `json:"filters"`
 }
 
+type alertUpdateRequest struct {
+	Name    string        `json:"name,omitempty"`
+	Filters *AlertFilters `json:"filters"`
+}
+
 // CreateAlert creates a network alert for a defined IP/netblock which can be used to
 // subscribe to changes/events that are discovered within that range.
 func (c *Client) CreateAlert(ctx context.Context, name string, ip []string, expires int) (*Alert, error) {
@@ -68,6 +73,33 @@ func (c *Client) CreateAlert(ctx context.Context, name string, ip []string, expi
 	return &alert, nil
 }
 
+// UpdateAlert changes the name and the monitored IP/netblocks of the network alert keeping its triggers
+// and notifiers. The name is left unchanged if empty.
+func (c *Client) UpdateAlert(ctx context.Context, id string, name string, ip []string) (*Alert, error) {
+	var alert Alert
+	path := fmt.Sprintf(alertUpdatePath, id)
+	payload := &alertUpdateRequest{
+		Name:    name,
+		Filters: &AlertFilters{IP: ip},
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.NewRequest("POST", path, nil, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Do(ctx, req, &alert); err != nil {
+		return nil, err
+	}
+
+	return &alert, nil
+}
+
 // GetAlerts returns a listing of all the network alerts that are currently active on the account.
 func (c *Client) GetAlerts(ctx context.Context) ([]*Alert, error) {
 	alerts := make([]*Alert, 0)
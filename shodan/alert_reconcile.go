@@ -0,0 +1,370 @@
+package shodan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AlertActionType is a kind of change made by the alert reconciler.
+type AlertActionType string
+
+const (
+	AlertActionCreate         AlertActionType = "create"
+	AlertActionUpdate         AlertActionType = "update"
+	AlertActionDelete         AlertActionType = "delete"
+	AlertActionEnableTrigger  AlertActionType = "enable_trigger"
+	AlertActionDisableTrigger AlertActionType = "disable_trigger"
+	AlertActionIgnoreService  AlertActionType = "ignore_service"
+	AlertActionRestoreService AlertActionType = "restore_service"
+	AlertActionAddNotifier    AlertActionType = "add_notifier"
+	AlertActionRemoveNotifier AlertActionType = "remove_notifier"
+)
+
+// DesiredAlert is the expected state of a network alert. Alerts are matched by name.
+type DesiredAlert struct {
+	Name string `json:"name"`
+
+	// IP holds the monitored IPs and netblocks.
+	IP []string `json:"ip"`
+
+	// Expires is the lifetime of the alert in seconds, used only when the alert is created.
+	Expires int `json:"expires,omitempty"`
+
+	// Triggers are the enabled triggers with their whitelisted services. Nil leaves the triggers
+	// of an existing alert as is, an empty value disables all of them.
+	Triggers AlertTriggers `json:"triggers,omitempty"`
+
+	// Notifiers are IDs of the notifiers attached to the alert. Nil leaves the notifiers
+	// of an existing alert as is, an empty value removes all of them.
+	Notifiers []string `json:"notifiers,omitempty"`
+}
+
+// AlertReconcileOptions configures alert reconciliation.
+type AlertReconcileOptions struct {
+	// Prune deletes the alerts that are not desired.
+	Prune bool
+
+	// DryRun only plans the changes without applying them.
+	DryRun bool
+}
+
+// AlertAction is a single change of the alert plan.
+type AlertAction struct {
+	Type AlertActionType
+
+	// AlertID is empty for the alerts being created.
+	AlertID   string
+	AlertName string
+
+	// IP is set for create and update actions.
+	IP []string
+
+	// Expires is set for create actions.
+	Expires int
+
+	Trigger AlertTriggerName
+
+	// Service is the whitelisted service in ip:port form.
+	Service string
+
+	Notifier string
+}
+
+// AlertPlan is the list of changes that bring the current alerts to the desired state.
+type AlertPlan struct {
+	Actions []*AlertAction
+}
+
+// String returns the action in a human readable form.
+func (a *AlertAction) String() string {
+	switch a.Type {
+	case AlertActionCreate:
+		return fmt.Sprintf("+ create alert %q %v", a.AlertName, a.IP)
+	case AlertActionUpdate:
+		return fmt.Sprintf("~ update alert %q (%s) %v", a.AlertName, a.AlertID, a.IP)
+	case AlertActionDelete:
+		return fmt.Sprintf("- delete alert %q (%s)", a.AlertName, a.AlertID)
+	case AlertActionEnableTrigger:
+		return fmt.Sprintf("+ enable trigger %q on alert %q", a.Trigger, a.AlertName)
+	case AlertActionDisableTrigger:
+		return fmt.Sprintf("- disable trigger %q on alert %q", a.Trigger, a.AlertName)
+	case AlertActionIgnoreService:
+		return fmt.Sprintf("+ ignore service %q for trigger %q on alert %q", a.Service, a.Trigger, a.AlertName)
+	case AlertActionRestoreService:
+		return fmt.Sprintf("- stop ignoring service %q for trigger %q on alert %q", a.Service, a.Trigger, a.AlertName)
+	case AlertActionAddNotifier:
+		return fmt.Sprintf("+ add notifier %q to alert %q", a.Notifier, a.AlertName)
+	case AlertActionRemoveNotifier:
+		return fmt.Sprintf("- remove notifier %q from alert %q", a.Notifier, a.AlertName)
+	}
+
+	return fmt.Sprintf("? %s alert %q", a.Type, a.AlertName)
+}
+
+// Empty reports whether there is nothing to change.
+func (p *AlertPlan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// String returns the plan one action per line, suitable for the dry run output.
+func (p *AlertPlan) String() string {
+	if p.Empty() {
+		return "no changes\n"
+	}
+
+	var b strings.Builder
+	for _, action := range p.Actions {
+		b.WriteString(action.String())
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// PlanAlerts compares the desired alerts with the alerts of the account and returns the changes to apply.
+func (c *Client) PlanAlerts(
+	ctx context.Context,
+	desired []*DesiredAlert,
+	options *AlertReconcileOptions,
+) (*AlertPlan, error) {
+	current, err := c.GetAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prune := options != nil && options.Prune
+
+	return planAlerts(current, desired, prune)
+}
+
+// ApplyAlertPlan applies the changes of the plan in order. It stops at the first failed action.
+func (c *Client) ApplyAlertPlan(ctx context.Context, plan *AlertPlan) error {
+	created := make(map[string]string)
+
+	for _, action := range plan.Actions {
+		id := action.AlertID
+		if id == "" {
+			id = created[action.AlertName]
+		}
+
+		var err error
+
+		switch action.Type {
+		case AlertActionCreate:
+			var alert *Alert
+			if alert, err = c.CreateAlert(ctx, action.AlertName, action.IP, action.Expires); err == nil {
+				created[action.AlertName] = alert.ID
+			}
+		case AlertActionUpdate:
+			_, err = c.UpdateAlert(ctx, id, "", action.IP)
+		case AlertActionDelete:
+			_, err = c.DeleteAlert(ctx, id)
+		case AlertActionEnableTrigger:
+			_, err = c.EnableAlertTrigger(ctx, &AlertTriggerIdent{AlertID: id, TriggerName: string(action.Trigger)})
+		case AlertActionDisableTrigger:
+			_, err = c.DisableAlertTrigger(ctx, &AlertTriggerIdent{AlertID: id, TriggerName: string(action.Trigger)})
+		case AlertActionIgnoreService:
+			_, err = c.AddServiceToAlertTriggerWhitelist(ctx, action.serviceIdent(id))
+		case AlertActionRestoreService:
+			_, err = c.RemoveServiceFromAlertTriggerWhitelist(ctx, action.serviceIdent(id))
+		case AlertActionAddNotifier:
+			_, err = c.AddAlertNotifier(ctx, id, action.Notifier)
+		case AlertActionRemoveNotifier:
+			_, err = c.DeleteAlertNotifier(ctx, id, action.Notifier)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", action, err)
+		}
+	}
+
+	return nil
+}
+
+// ReconcileAlerts brings the alerts of the account to the desired state and returns the applied plan.
+// Nothing is changed in the dry run mode.
+func (c *Client) ReconcileAlerts(
+	ctx context.Context,
+	desired []*DesiredAlert,
+	options *AlertReconcileOptions,
+) (*AlertPlan, error) {
+	plan, err := c.PlanAlerts(ctx, desired, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil && options.DryRun {
+		return plan, nil
+	}
+
+	return plan, c.ApplyAlertPlan(ctx, plan)
+}
+
+func (a *AlertAction) serviceIdent(alertID string) *AlertTriggerServiceIdent {
+	return &AlertTriggerServiceIdent{
+		AlertTriggerIdent: &AlertTriggerIdent{AlertID: alertID, TriggerName: string(a.Trigger)},
+		ServiceName:       a.Service,
+	}
+}
+
+func planAlerts(current []*Alert, desired []*DesiredAlert, prune bool) (*AlertPlan, error) {
+	plan := &AlertPlan{Actions: make([]*AlertAction, 0)}
+
+	byName := make(map[string]*Alert, len(current))
+	for _, alert := range current {
+		if _, ok := byName[alert.Name]; ok {
+			return nil, fmt.Errorf("%w: several alerts are named %q", ErrInvalidAlert, alert.Name)
+		}
+
+		byName[alert.Name] = alert
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		if d.Name == "" || len(d.IP) == 0 {
+			return nil, fmt.Errorf("%w: alert must have a name and networks", ErrInvalidAlert)
+		}
+
+		if wanted[d.Name] {
+			return nil, fmt.Errorf("%w: alert %q is declared twice", ErrInvalidAlert, d.Name)
+		}
+
+		wanted[d.Name] = true
+		plan.Actions = append(plan.Actions, planAlert(byName[d.Name], d)...)
+	}
+
+	if !prune {
+		return plan, nil
+	}
+
+	for _, alert := range current {
+		if !wanted[alert.Name] {
+			plan.Actions = append(plan.Actions, &AlertAction{
+				Type:      AlertActionDelete,
+				AlertID:   alert.ID,
+				AlertName: alert.Name,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+func planAlert(alert *Alert, d *DesiredAlert) []*AlertAction {
+	actions := make([]*AlertAction, 0)
+	newAction := func(actionType AlertActionType) *AlertAction {
+		action := &AlertAction{Type: actionType, AlertName: d.Name}
+		if alert != nil {
+			action.AlertID = alert.ID
+		}
+
+		return action
+	}
+
+	var (
+		currentIP, currentNotifiers []string
+		currentTriggers             AlertTriggers
+	)
+
+	if alert == nil {
+		action := newAction(AlertActionCreate)
+		action.IP = d.IP
+		action.Expires = d.Expires
+		actions = append(actions, action)
+	} else {
+		if alert.Filters != nil {
+			currentIP = alert.Filters.IP
+		}
+
+		currentTriggers = alert.Triggers
+
+		for _, notifier := range alert.Notifiers {
+			currentNotifiers = append(currentNotifiers, notifier.ID)
+		}
+	}
+
+	if alert != nil && !sameStrings(currentIP, d.IP) {
+		action := newAction(AlertActionUpdate)
+		action.IP = d.IP
+		actions = append(actions, action)
+	}
+
+	if d.Triggers != nil {
+		triggers := DiffAlertTriggers(currentTriggers, d.Triggers)
+		for _, name := range triggers.Enable {
+			action := newAction(AlertActionEnableTrigger)
+			action.Trigger = name
+			actions = append(actions, action)
+		}
+
+		for _, name := range triggers.Disable {
+			action := newAction(AlertActionDisableTrigger)
+			action.Trigger = name
+			actions = append(actions, action)
+		}
+
+		for _, service := range triggers.Ignore {
+			action := newAction(AlertActionIgnoreService)
+			action.Trigger, action.Service = service.Trigger, service.Service
+			actions = append(actions, action)
+		}
+
+		for _, service := range triggers.Unignore {
+			action := newAction(AlertActionRestoreService)
+			action.Trigger, action.Service = service.Trigger, service.Service
+			actions = append(actions, action)
+		}
+	}
+
+	if d.Notifiers != nil {
+		added, removed := diffStrings(currentNotifiers, d.Notifiers)
+		for _, id := range added {
+			action := newAction(AlertActionAddNotifier)
+			action.Notifier = id
+			actions = append(actions, action)
+		}
+
+		for _, id := range removed {
+			action := newAction(AlertActionRemoveNotifier)
+			action.Notifier = id
+			actions = append(actions, action)
+		}
+	}
+
+	return actions
+}
+
+// diffStrings returns sorted values present only in to (added) and only in from (removed).
+func diffStrings(from, to []string) (added, removed []string) {
+	fromSet := make(map[string]bool, len(from))
+	for _, value := range from {
+		fromSet[value] = true
+	}
+
+	toSet := make(map[string]bool, len(to))
+	for _, value := range to {
+		if !fromSet[value] && !toSet[value] {
+			added = append(added, value)
+		}
+
+		toSet[value] = true
+	}
+
+	for value := range fromSet {
+		if !toSet[value] {
+			removed = append(removed, value)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return added, removed
+}
+
+func sameStrings(a, b []string) bool {
+	added, removed := diffStrings(a, b)
+	return len(added) == 0 && len(removed) == 0
+}
@@ -0,0 +1,156 @@
+package shodan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_PlanAlerts(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(alertsInfoListPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "alert/reconcile_alerts"))
+	})
+
+	desired := []*DesiredAlert{
+		{
+			Name: "office",
+			IP:   []string{"198.20.22.0/24", "198.20.23.0/24"},
+			Triggers: AlertTriggers{
+				AlertTriggerMalware:      {Ignore: []string{"198.20.22.2:443"}},
+				AlertTriggerOpenDatabase: {},
+			},
+			Notifiers: []string{"default"},
+		},
+		{
+			Name:      "datacenter",
+			IP:        []string{"198.20.99.0/24"},
+			Triggers:  NewAlertTriggers(AlertTriggerNewService),
+			Notifiers: []string{"slack"},
+		},
+	}
+
+	plan, err := client.PlanAlerts(context.TODO(), desired, &AlertReconcileOptions{Prune: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `~ update alert "office" (ZZ4TDUUORVE1DIIP) [198.20.22.0/24 198.20.23.0/24]
++ enable trigger "open_database" on alert "office"
+- disable trigger "iot" on alert "office"
++ ignore service "198.20.22.2:443" for trigger "malware" on alert "office"
+- stop ignoring service "198.20.22.1:80" for trigger "malware" on alert "office"
++ create alert "datacenter" [198.20.99.0/24]
++ enable trigger "new_service" on alert "datacenter"
++ add notifier "slack" to alert "datacenter"
+- delete alert "legacy" (IU0CJDXNNEXBOPK3)
+`, plan.String())
+
+	plan, err = client.PlanAlerts(context.TODO(), desired[:1], nil)
+
+	assert.Nil(t, err)
+	assert.Len(t, plan.Actions, 5)
+}
+
+func TestClient_PlanAlertsUnmanaged(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(alertsInfoListPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "alert/reconcile_alerts"))
+	})
+
+	plan, err := client.PlanAlerts(context.TODO(), []*DesiredAlert{{Name: "office", IP: []string{"198.20.22.0/24"}}}, nil)
+
+	assert.Nil(t, err)
+	assert.Empty(t, plan.Actions)
+
+	desired := []*DesiredAlert{
+		{Name: "office", IP: []string{"198.20.22.0/24"}, Triggers: AlertTriggers{}, Notifiers: []string{}},
+	}
+
+	plan, err = client.PlanAlerts(context.TODO(), desired, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `- disable trigger "iot" on alert "office"
+- disable trigger "malware" on alert "office"
+- remove notifier "default" from alert "office"
+`, plan.String())
+}
+
+func TestClient_PlanAlertsInvalid(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(alertsInfoListPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "alert/reconcile_alerts"))
+	})
+
+	_, err := client.PlanAlerts(context.TODO(), []*DesiredAlert{{Name: "office"}}, nil)
+	assert.True(t, errors.Is(err, ErrInvalidAlert))
+
+	desired := []*DesiredAlert{
+		{Name: "office", IP: []string{"198.20.22.0/24"}},
+		{Name: "office", IP: []string{"198.20.23.0/24"}},
+	}
+
+	_, err = client.PlanAlerts(context.TODO(), desired, nil)
+	assert.True(t, errors.Is(err, ErrInvalidAlert))
+}
+
+func TestClient_ReconcileAlerts(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	calls := make([]string, 0)
+	record := func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		fmt.Fprint(w, `{"success": true}`)
+	}
+
+	mux.HandleFunc(alertsInfoListPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "alert/reconcile_alerts"))
+	})
+	mux.HandleFunc(alertCreatePath, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Write(getStub(t, "alert/create_alert"))
+	})
+	mux.HandleFunc(alertCreatePath+"/", record)
+
+	desired := []*DesiredAlert{
+		{
+			Name: "office",
+			IP:   []string{"198.20.22.0/24"},
+			Triggers: AlertTriggers{
+				AlertTriggerMalware: {Ignore: []string{"198.20.22.1:80"}},
+				AlertTriggerIOT:     {},
+			},
+			Notifiers: []string{"default"},
+		},
+		{Name: "datacenter", IP: []string{"198.20.99.0/24"}, Triggers: NewAlertTriggers(AlertTriggerNewService)},
+	}
+
+	plan, err := client.ReconcileAlerts(context.TODO(), desired, &AlertReconcileOptions{DryRun: true})
+
+	assert.Nil(t, err)
+	assert.Len(t, plan.Actions, 2)
+	assert.Empty(t, calls)
+
+	plan, err = client.ReconcileAlerts(context.TODO(), desired, &AlertReconcileOptions{Prune: true})
+
+	assert.Nil(t, err)
+	assert.Len(t, plan.Actions, 3)
+	assert.Equal(t, []string{
+		"POST /shodan/alert",
+		"PUT /shodan/alert/JZT8NVWEZWCY79OO/trigger/new_service",
+		"DELETE /shodan/alert/IU0CJDXNNEXBOPK3",
+	}, calls)
+}
+
+func TestAlertPlan_String(t *testing.T) {
+	assert.Equal(t, "no changes\n", (&AlertPlan{}).String())
+}
@@ -3,6 +3,7 @@ package shodan
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"testing"
 
@@ -162,3 +163,27 @@ func TestClient_CreateAlert(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, alertExpected, alert)
 }
+
+func TestClient_UpdateAlert(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	id := "ZZ4TDUUORVE1DIIP"
+	path := fmt.Sprintf(alertUpdatePath, id)
+
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"filters": {"ip": ["198.20.22.0/24"]}}`, string(body))
+
+		w.Write(getStub(t, "alert/alert"))
+	})
+
+	alert, err := client.UpdateAlert(context.TODO(), id, "", []string{"198.20.22.0/24"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, id, alert.ID)
+	assert.Equal(t, []string{"198.20.22.0/24"}, alert.Filters.IP)
+}
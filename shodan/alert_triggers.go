@@ -3,6 +3,7 @@ package shodan
 import (
 	"context"
 	"fmt"
+	"sort"
 )
 
 const (
@@ -11,11 +12,53 @@ const (
 	alertTriggerWhitelistPath = "/shodan/alert/%s/trigger/%s/ignore/%s"
 )
 
+// AlertTriggerName is a name of the alert trigger.
+type AlertTriggerName string
+
+// Known alert triggers, see GetAlertTriggers for the full list.
+const (
+	AlertTriggerAny                     AlertTriggerName = "any"
+	AlertTriggerIndustrialControlSystem AlertTriggerName = "industrial_control_system"
+	AlertTriggerInternetScanner         AlertTriggerName = "internet_scanner"
+	AlertTriggerIOT                     AlertTriggerName = "iot"
+	AlertTriggerMalware                 AlertTriggerName = "malware"
+	AlertTriggerNewService              AlertTriggerName = "new_service"
+	AlertTriggerOpenDatabase            AlertTriggerName = "open_database"
+	AlertTriggerSSLExpired              AlertTriggerName = "ssl_expired"
+	AlertTriggerUncommon                AlertTriggerName = "uncommon"
+	AlertTriggerUncommonPlus            AlertTriggerName = "uncommon_plus"
+	AlertTriggerVulnerable              AlertTriggerName = "vulnerable"
+	AlertTriggerVulnerableUnverified    AlertTriggerName = "vulnerable_unverified"
+)
+
 // AlertTrigger represents a trigger.
 type AlertTrigger struct {
-	Name        string `json:"name"`
-	Rule        string `json:"rule"`
-	Description string `json:"description"`
+	Name        AlertTriggerName `json:"name"`
+	Rule        string           `json:"rule"`
+	Description string           `json:"description"`
+}
+
+// AlertTriggerState is the state of the trigger enabled on the alert.
+type AlertTriggerState struct {
+	// Ignore holds the whitelisted services in ip:port form.
+	Ignore []string `json:"ignore,omitempty"`
+}
+
+// AlertTriggers maps the enabled triggers of the alert to their state. Missing triggers are disabled.
+type AlertTriggers map[AlertTriggerName]*AlertTriggerState
+
+// AlertTriggerService is a service whitelisted for the trigger.
+type AlertTriggerService struct {
+	Trigger AlertTriggerName
+	Service string
+}
+
+// AlertTriggersDelta is the difference between two trigger configurations.
+type AlertTriggersDelta struct {
+	Enable   []AlertTriggerName
+	Disable  []AlertTriggerName
+	Ignore   []*AlertTriggerService
+	Unignore []*AlertTriggerService
 }
 
 type AlertTriggerIdent struct {
@@ -28,6 +71,84 @@ type AlertTriggerServiceIdent struct {
 	ServiceName string
 }
 
+// NewAlertTriggers creates the configuration with the triggers enabled and nothing whitelisted.
+func NewAlertTriggers(names ...AlertTriggerName) AlertTriggers {
+	triggers := make(AlertTriggers, len(names))
+	for _, name := range names {
+		triggers[name] = &AlertTriggerState{}
+	}
+
+	return triggers
+}
+
+// Enabled reports whether the trigger is enabled.
+func (t AlertTriggers) Enabled(name AlertTriggerName) bool {
+	_, ok := t[name]
+	return ok
+}
+
+// Ignored returns the services whitelisted for the trigger.
+func (t AlertTriggers) Ignored(name AlertTriggerName) []string {
+	if state := t[name]; state != nil {
+		return state.Ignore
+	}
+
+	return nil
+}
+
+// Names returns the sorted names of the enabled triggers.
+func (t AlertTriggers) Names() []AlertTriggerName {
+	names := make([]AlertTriggerName, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// DiffAlertTriggers returns the changes that turn the from configuration into the to configuration.
+// Whitelists of the disabled triggers are not reported.
+func DiffAlertTriggers(from, to AlertTriggers) *AlertTriggersDelta {
+	delta := &AlertTriggersDelta{
+		Enable:   make([]AlertTriggerName, 0),
+		Disable:  make([]AlertTriggerName, 0),
+		Ignore:   make([]*AlertTriggerService, 0),
+		Unignore: make([]*AlertTriggerService, 0),
+	}
+
+	for _, name := range to.Names() {
+		if !from.Enabled(name) {
+			delta.Enable = append(delta.Enable, name)
+		}
+
+		added, removed := diffStrings(from.Ignored(name), to.Ignored(name))
+		for _, service := range added {
+			delta.Ignore = append(delta.Ignore, &AlertTriggerService{Trigger: name, Service: service})
+		}
+
+		for _, service := range removed {
+			delta.Unignore = append(delta.Unignore, &AlertTriggerService{Trigger: name, Service: service})
+		}
+	}
+
+	for _, name := range from.Names() {
+		if !to.Enabled(name) {
+			delta.Disable = append(delta.Disable, name)
+		}
+	}
+
+	return delta
+}
+
+// Empty reports whether the configurations are the same.
+func (d *AlertTriggersDelta) Empty() bool {
+	return len(d.Enable) == 0 && len(d.Disable) == 0 && len(d.Ignore) == 0 && len(d.Unignore) == 0
+}
+
 // Returns a list of all the triggers that can be enabled on network alerts.
 func (c *Client) GetAlertTriggers(ctx context.Context) ([]*AlertTrigger, error) {
 	triggers := make([]*AlertTrigger, 0)
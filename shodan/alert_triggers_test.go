@@ -2,6 +2,7 @@ package shodan
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -119,3 +120,43 @@ func TestClient_RemoveServiceFromAlertTriggerWhitelist(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, r)
 }
+
+func TestAlertTriggers_UnmarshalJSON(t *testing.T) {
+	var alert Alert
+
+	err := json.Unmarshal([]byte(`{"triggers": {"malware": {"ignore": ["198.20.22.1:80"]}, "iot": {}}}`), &alert)
+
+	assert.Nil(t, err)
+	assert.True(t, alert.Triggers.Enabled(AlertTriggerMalware))
+	assert.True(t, alert.Triggers.Enabled(AlertTriggerIOT))
+	assert.False(t, alert.Triggers.Enabled(AlertTriggerNewService))
+	assert.Equal(t, []string{"198.20.22.1:80"}, alert.Triggers.Ignored(AlertTriggerMalware))
+	assert.Empty(t, alert.Triggers.Ignored(AlertTriggerIOT))
+	assert.Equal(t, []AlertTriggerName{AlertTriggerIOT, AlertTriggerMalware}, alert.Triggers.Names())
+}
+
+func TestDiffAlertTriggers(t *testing.T) {
+	from := AlertTriggers{
+		AlertTriggerMalware: {Ignore: []string{"198.20.22.1:80", "198.20.22.2:22"}},
+		AlertTriggerIOT:     {},
+	}
+	to := AlertTriggers{
+		AlertTriggerMalware:      {Ignore: []string{"198.20.22.2:22", "198.20.22.3:443"}},
+		AlertTriggerOpenDatabase: {Ignore: []string{"198.20.22.4:27017"}},
+	}
+
+	delta := DiffAlertTriggers(from, to)
+
+	assert.Equal(t, []AlertTriggerName{AlertTriggerOpenDatabase}, delta.Enable)
+	assert.Equal(t, []AlertTriggerName{AlertTriggerIOT}, delta.Disable)
+	assert.Equal(t, []*AlertTriggerService{
+		{Trigger: AlertTriggerMalware, Service: "198.20.22.3:443"},
+		{Trigger: AlertTriggerOpenDatabase, Service: "198.20.22.4:27017"},
+	}, delta.Ignore)
+	assert.Equal(t, []*AlertTriggerService{
+		{Trigger: AlertTriggerMalware, Service: "198.20.22.1:80"},
+	}, delta.Unignore)
+
+	assert.True(t, DiffAlertTriggers(to, to).Empty())
+	assert.True(t, DiffAlertTriggers(nil, NewAlertTriggers()).Empty())
+}
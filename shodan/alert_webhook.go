@@ -0,0 +1,136 @@
+package shodan
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Headers sent by Shodan along with the banner to webhook notifiers.
+const (
+	AlertIDHeader        = "Shodan-Alert-Id"
+	AlertNameHeader      = "Shodan-Alert-Name"
+	AlertTriggerHeader   = "Shodan-Alert-Trigger"
+	AlertSignatureHeader = "Shodan-Signature-Sha1"
+)
+
+const defaultAlertWebhookMaxBodySize = 10 << 20
+
+// AlertNotification is the banner matched by the alert trigger and delivered to the webhook.
+type AlertNotification struct {
+	AlertID   string
+	AlertName string
+	Trigger   AlertTriggerName
+	Banner    *HostData
+}
+
+// AlertNotificationFunc handles the received notification. A returned error makes the handler
+// respond with 500 Internal Server Error.
+type AlertNotificationFunc func(ctx context.Context, notification *AlertNotification) error
+
+// AlertWebhookHandler is http.Handler receiving alert notifications sent to the webhook notifier.
+type AlertWebhookHandler struct {
+	key    string
+	handle AlertNotificationFunc
+
+	// MaxBodySize limits the size of the accepted banner (10MB by default).
+	MaxBodySize int64
+}
+
+// NewAlertWebhookHandler creates the handler verifying the notifications with the API key
+// of the account owning the alerts and passing them to the function. Signatures are not verified
+// if the key is empty.
+func NewAlertWebhookHandler(key string, handle AlertNotificationFunc) *AlertWebhookHandler {
+	return &AlertWebhookHandler{key: key, handle: handle, MaxBodySize: defaultAlertWebhookMaxBodySize}
+}
+
+// NewAlertWebhookChannel creates the handler sending the notifications to the channel. The request
+// is blocked until the notification is received from the channel or the client goes away.
+func NewAlertWebhookChannel(key string, ch chan<- *AlertNotification) *AlertWebhookHandler {
+	return NewAlertWebhookHandler(key, func(ctx context.Context, notification *AlertNotification) error {
+		select {
+		case ch <- notification:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// ServeHTTP validates and decodes the notification and passes it to the handler function.
+func (h *AlertWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	maxBodySize := h.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultAlertWebhookMaxBodySize
+	}
+
+	notification, err := DecodeAlertNotification(http.MaxBytesReader(w, r.Body, maxBodySize), r.Header, h.key)
+
+	switch {
+	case errors.Is(err, ErrInvalidSignature):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.handle(r.Context(), notification); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DecodeAlertNotification reads the banner from the body and the alert metadata from the headers.
+// The signature is verified with the API key unless the key is empty.
+func DecodeAlertNotification(body io.Reader, header http.Header, key string) (*AlertNotification, error) {
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != "" && !validAlertSignature(b, header.Get(AlertSignatureHeader), key) {
+		return nil, ErrInvalidSignature
+	}
+
+	var banner HostData
+	if err := json.Unmarshal(b, &banner); err != nil {
+		return nil, fmt.Errorf("could not decode banner: %w", err)
+	}
+
+	return &AlertNotification{
+		AlertID:   header.Get(AlertIDHeader),
+		AlertName: header.Get(AlertNameHeader),
+		Trigger:   AlertTriggerName(header.Get(AlertTriggerHeader)),
+		Banner:    &banner,
+	}, nil
+}
+
+// validAlertSignature checks the hex encoded HMAC-SHA1 of the body.
+func validAlertSignature(body []byte, signature, key string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha1.New, []byte(key))
+	mac.Write(body)
+
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,106 @@
+package shodan
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testAlertBanner = `{"ip_str": "198.20.22.1", "port": 27017, "transport": "tcp", "product": "MongoDB"}`
+
+func newAlertWebhookRequest(body, key string) *http.Request {
+	req := httptest.NewRequest("POST", "/shodan", strings.NewReader(body))
+	req.Header.Set(AlertIDHeader, "ZZ4TDUUORVE1DIIP")
+	req.Header.Set(AlertNameHeader, "office")
+	req.Header.Set(AlertTriggerHeader, "open_database")
+
+	mac := hmac.New(sha1.New, []byte(key))
+	mac.Write([]byte(body))
+	req.Header.Set(AlertSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+
+	return req
+}
+
+func TestAlertWebhookHandler(t *testing.T) {
+	var received *AlertNotification
+
+	handler := NewAlertWebhookHandler("TEST_TOKEN", func(ctx context.Context, n *AlertNotification) error {
+		received = n
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newAlertWebhookRequest(testAlertBanner, "TEST_TOKEN"))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "ZZ4TDUUORVE1DIIP", received.AlertID)
+	assert.Equal(t, "office", received.AlertName)
+	assert.Equal(t, AlertTriggerOpenDatabase, received.Trigger)
+	assert.Equal(t, "198.20.22.1", received.Banner.IP.String())
+	assert.Equal(t, 27017, received.Banner.Port)
+	assert.Equal(t, "MongoDB", received.Banner.Product)
+}
+
+func TestAlertWebhookHandler_Errors(t *testing.T) {
+	calls := 0
+	handler := NewAlertWebhookHandler("TEST_TOKEN", func(ctx context.Context, n *AlertNotification) error {
+		calls++
+		return errors.New("storage is down")
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newAlertWebhookRequest(testAlertBanner, "WRONG_TOKEN"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newAlertWebhookRequest(`{"port": "broken"`, "TEST_TOKEN"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/shodan", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	assert.Equal(t, 0, calls)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newAlertWebhookRequest(testAlertBanner, "TEST_TOKEN"))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, 1, calls)
+
+	handler.MaxBodySize = 10
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newAlertWebhookRequest(testAlertBanner, "TEST_TOKEN"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAlertWebhookChannel(t *testing.T) {
+	ch := make(chan *AlertNotification, 1)
+	server := httptest.NewServer(NewAlertWebhookChannel("", ch))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(testAlertBanner))
+	assert.Nil(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	notification := <-ch
+	assert.Equal(t, 27017, notification.Banner.Port)
+	assert.Empty(t, notification.AlertID)
+}
+
+func TestDecodeAlertNotification(t *testing.T) {
+	req := newAlertWebhookRequest(testAlertBanner, "TEST_TOKEN")
+	req.Header.Set(AlertSignatureHeader, "not hex")
+
+	_, err := DecodeAlertNotification(req.Body, req.Header, "TEST_TOKEN")
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+}
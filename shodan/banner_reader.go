@@ -0,0 +1,237 @@
+package shodan
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+const (
+	bannerReaderBufferSize = 64 << 10
+	bannerBatchSize        = 64
+)
+
+// BannerReaderOptions configures BannerReader.
+type BannerReaderOptions struct {
+	// Workers is the number of goroutines decoding banners in parallel. Banners are decoded
+	// in the calling goroutine if less than 2. The order of banners is preserved anyway.
+	Workers int
+}
+
+// BannerReader reads newline-delimited JSON banners, i.e. Shodan datasets and exported search results.
+// Gzip compressed input is detected automatically. Only a bounded number of banners is kept in memory.
+type BannerReader struct {
+	lines  *bufio.Reader
+	gzip   *gzip.Reader
+	read   int
+	lineNo int
+	err    error
+
+	// parallel mode
+	pending chan *bannerBatch
+	done    chan struct{}
+	batch   *bannerBatch
+	index   int
+	close   sync.Once
+}
+
+// bannerBatch is a chunk of lines decoded by a worker. done is closed once results are ready.
+type bannerBatch struct {
+	numbers []int
+	lines   [][]byte
+	results []*HostData
+	errors  []error
+	err     error
+	done    chan struct{}
+}
+
+// NewBannerReader creates a reader of banners. An error is returned if the gzip header is malformed.
+func NewBannerReader(r io.Reader, options *BannerReaderOptions) (*BannerReader, error) {
+	br := bufio.NewReaderSize(r, bannerReaderBufferSize)
+	reader := &BannerReader{lines: br}
+
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+
+		reader.gzip = gz
+		reader.lines = bufio.NewReaderSize(gz, bannerReaderBufferSize)
+	}
+
+	if options != nil && options.Workers > 1 {
+		reader.startWorkers(options.Workers)
+	}
+
+	return reader, nil
+}
+
+// Next returns the next banner. It returns *BannerDecodeError for a malformed line, reading can be
+// continued after it. io.EOF is returned at the end of the input.
+func (r *BannerReader) Next() (*HostData, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if r.pending != nil {
+		return r.nextDecoded()
+	}
+
+	number, line, err := r.readLine()
+	if err != nil {
+		r.err = err
+		return nil, err
+	}
+
+	r.lineNo = number
+
+	return decodeBanner(number, line)
+}
+
+// Line returns the line number of the last banner returned by Next.
+func (r *BannerReader) Line() int {
+	return r.lineNo
+}
+
+// Close stops the decoding goroutines and releases the gzip reader. It doesn't close the underlying reader.
+// Next returns ErrReaderClosed after Close.
+func (r *BannerReader) Close() error {
+	r.err = ErrReaderClosed
+
+	if r.done != nil {
+		// the gzip reader is released by the reading goroutine
+		r.close.Do(func() { close(r.done) })
+		return nil
+	}
+
+	if r.gzip != nil {
+		return r.gzip.Close()
+	}
+
+	return nil
+}
+
+// readLine returns the next non-empty line with its number.
+func (r *BannerReader) readLine() (int, []byte, error) {
+	for {
+		chunk, err := r.lines.ReadBytes('\n')
+		if err != nil && len(bytes.TrimSpace(chunk)) == 0 {
+			return 0, nil, err
+		}
+
+		r.read++
+
+		if chunk = bytes.TrimSpace(chunk); len(chunk) > 0 {
+			return r.read, chunk, nil
+		}
+	}
+}
+
+func decodeBanner(line int, data []byte) (*HostData, error) {
+	banner := new(HostData)
+	if err := json.Unmarshal(data, banner); err != nil {
+		return nil, &BannerDecodeError{Line: line, Data: data, Err: err}
+	}
+
+	return banner, nil
+}
+
+func (r *BannerReader) startWorkers(workers int) {
+	jobs := make(chan *bannerBatch, workers)
+	r.pending = make(chan *bannerBatch, workers*2)
+	r.done = make(chan struct{})
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for batch := range jobs {
+				for i, line := range batch.lines {
+					batch.results[i], batch.errors[i] = decodeBanner(batch.numbers[i], line)
+				}
+
+				close(batch.done)
+			}
+		}()
+	}
+
+	go r.readBatches(jobs)
+}
+
+// readBatches splits the input into batches. The last batch carries the error that stopped reading.
+func (r *BannerReader) readBatches(jobs chan<- *bannerBatch) {
+	defer close(jobs)
+	defer close(r.pending)
+
+	if r.gzip != nil {
+		defer r.gzip.Close()
+	}
+
+	for {
+		batch := &bannerBatch{done: make(chan struct{})}
+
+		for len(batch.lines) < bannerBatchSize {
+			number, line, err := r.readLine()
+			if err != nil {
+				batch.err = err
+				break
+			}
+
+			batch.numbers = append(batch.numbers, number)
+			batch.lines = append(batch.lines, line)
+		}
+
+		batch.results = make([]*HostData, len(batch.lines))
+		batch.errors = make([]error, len(batch.lines))
+
+		select {
+		case r.pending <- batch:
+		case <-r.done:
+			return
+		}
+
+		select {
+		case jobs <- batch:
+		case <-r.done:
+			return
+		}
+
+		if batch.err != nil {
+			return
+		}
+	}
+}
+
+func (r *BannerReader) nextDecoded() (*HostData, error) {
+	for r.batch == nil || r.index >= len(r.batch.lines) {
+		if r.batch != nil && r.batch.err != nil {
+			r.err = r.batch.err
+		}
+
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		batch, ok := <-r.pending
+		if !ok {
+			r.err = io.EOF
+			return nil, r.err
+		}
+
+		select {
+		case <-batch.done:
+		case <-r.done:
+			return nil, ErrReaderClosed
+		}
+
+		r.batch, r.index = batch, 0
+	}
+
+	i := r.index
+	r.index++
+	r.lineNo = r.batch.numbers[i]
+
+	return r.batch.results[i], r.batch.errors[i]
+}
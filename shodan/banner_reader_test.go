@@ -0,0 +1,129 @@
+package shodan
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testBanners = `{"ip_str": "198.20.0.1", "port": 80}
+
+{"ip_str": "198.20.0.2", "port": "broken"}
+{"ip_str": "198.20.0.3", "port": 22}`
+
+func readAllBanners(t *testing.T, r *BannerReader) ([]*HostData, []*BannerDecodeError) {
+	banners := make([]*HostData, 0)
+	failures := make([]*BannerDecodeError, 0)
+
+	for {
+		banner, err := r.Next()
+		if err == io.EOF {
+			return banners, failures
+		}
+
+		var decodeErr *BannerDecodeError
+		if errors.As(err, &decodeErr) {
+			failures = append(failures, decodeErr)
+			continue
+		}
+
+		assert.Nil(t, err)
+		banners = append(banners, banner)
+	}
+}
+
+func TestBannerReader(t *testing.T) {
+	r, err := NewBannerReader(strings.NewReader(testBanners), nil)
+	assert.Nil(t, err)
+
+	banner, err := r.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, 80, banner.Port)
+	assert.Equal(t, 1, r.Line())
+
+	banners, failures := readAllBanners(t, r)
+
+	assert.Len(t, banners, 1)
+	assert.Equal(t, 22, banners[0].Port)
+	assert.Equal(t, 4, r.Line())
+	assert.Len(t, failures, 1)
+	assert.Equal(t, 3, failures[0].Line)
+	assert.Contains(t, string(failures[0].Data), "broken")
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+	assert.Nil(t, r.Close())
+}
+
+func TestBannerReader_Gzip(t *testing.T) {
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(testBanners))
+	gz.Close()
+
+	r, err := NewBannerReader(&buf, nil)
+	assert.Nil(t, err)
+
+	defer r.Close()
+
+	banners, failures := readAllBanners(t, r)
+
+	assert.Len(t, banners, 2)
+	assert.Len(t, failures, 1)
+
+	_, err = NewBannerReader(bytes.NewReader([]byte{0x1f, 0x8b, 0x00}), nil)
+	assert.NotNil(t, err)
+}
+
+func TestBannerReader_Parallel(t *testing.T) {
+	var buf bytes.Buffer
+
+	for i := 1; i <= 1000; i++ {
+		if i%100 == 0 {
+			fmt.Fprintln(&buf, "not json")
+			continue
+		}
+
+		fmt.Fprintf(&buf, `{"port": %d}`+"\n", i)
+	}
+
+	r, err := NewBannerReader(&buf, &BannerReaderOptions{Workers: 4})
+	assert.Nil(t, err)
+
+	defer r.Close()
+
+	banners, failures := readAllBanners(t, r)
+
+	assert.Len(t, banners, 990)
+	assert.Len(t, failures, 10)
+
+	port := 0
+	for _, banner := range banners {
+		assert.True(t, banner.Port > port)
+		port = banner.Port
+	}
+
+	for i, failure := range failures {
+		assert.Equal(t, (i+1)*100, failure.Line)
+	}
+}
+
+func TestBannerReader_Close(t *testing.T) {
+	r, err := NewBannerReader(strings.NewReader(strings.Repeat(`{"port": 80}`+"\n", 1000)),
+		&BannerReaderOptions{Workers: 2})
+	assert.Nil(t, err)
+
+	_, err = r.Next()
+	assert.Nil(t, err)
+	assert.Nil(t, r.Close())
+
+	_, err = r.Next()
+	assert.True(t, errors.Is(err, ErrReaderClosed))
+}
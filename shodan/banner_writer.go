@@ -0,0 +1,261 @@
+package shodan
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const bannerFileTimeLayout = "20060102T150405"
+
+// BannerWriter writes banners as newline-delimited JSON, optionally gzip compressed. The output can be
+// read back with BannerReader. Keys not modeled by HostData are preserved.
+type BannerWriter struct {
+	w    io.Writer
+	gzip *gzip.Writer
+}
+
+// NewBannerWriter creates a writer of banners. Close must be called to flush the compressed output.
+func NewBannerWriter(w io.Writer, compress bool) *BannerWriter {
+	writer := &BannerWriter{w: w}
+	if compress {
+		writer.gzip = gzip.NewWriter(w)
+		writer.w = writer.gzip
+	}
+
+	return writer
+}
+
+// Write writes the banner as a single line.
+func (w *BannerWriter) Write(banner *HostData) error {
+	data, err := json.Marshal(banner)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.w.Write(append(data, '\n'))
+
+	return err
+}
+
+// WriteAll writes the banners, i.e. HostMatch.Matches or Host.Data.
+func (w *BannerWriter) WriteAll(banners []*HostData) error {
+	for _, banner := range banners {
+		if err := w.Write(banner); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush flushes the compressed data written so far.
+func (w *BannerWriter) Flush() error {
+	if w.gzip != nil {
+		return w.gzip.Flush()
+	}
+
+	return nil
+}
+
+// Close flushes the compressed output. It doesn't close the underlying writer.
+func (w *BannerWriter) Close() error {
+	if w.gzip != nil {
+		return w.gzip.Close()
+	}
+
+	return nil
+}
+
+// RotationOptions configures RotatingBannerWriter. A new file is started whenever any of the limits is reached.
+type RotationOptions struct {
+	// Dir is the directory to write the files to.
+	Dir string
+
+	// Prefix is the beginning of the file names (banners by default). Files are named
+	// <prefix>-<start time>[-<sequence>].json[.gz] where start time is truncated to the Interval.
+	Prefix string
+
+	// Compress enables gzip compression.
+	Compress bool
+
+	// MaxSize is the size of the file in bytes (compressed if enabled) after which the next file is started.
+	// The size is checked before writing a banner so files may be slightly larger. Not limited if zero.
+	MaxSize int64
+
+	// Interval is the period every file covers, i.e. time.Hour makes hourly files. Not limited if zero.
+	Interval time.Duration
+
+	// OnRotate is called with the path of every finished file.
+	OnRotate func(path string)
+
+	// Now returns the current time (time.Now by default).
+	Now func() time.Time
+}
+
+// RotatingBannerWriter writes banners into a series of files, see RotationOptions.
+type RotatingBannerWriter struct {
+	options RotationOptions
+
+	file    *os.File
+	writer  *BannerWriter
+	counter *countingWriter
+	path    string
+	period  time.Time
+	seq     int
+}
+
+// NewRotatingBannerWriter creates a writer of banner files. The first file is created on the first write.
+func NewRotatingBannerWriter(options *RotationOptions) *RotatingBannerWriter {
+	opts := *options
+	if opts.Prefix == "" {
+		opts.Prefix = "banners"
+	}
+
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	return &RotatingBannerWriter{options: opts}
+}
+
+// Write writes the banner starting a new file if needed.
+func (w *RotatingBannerWriter) Write(banner *HostData) error {
+	if err := w.rotate(); err != nil {
+		return err
+	}
+
+	return w.writer.Write(banner)
+}
+
+// WriteAll writes the banners, i.e. HostMatch.Matches or Host.Data.
+func (w *RotatingBannerWriter) WriteAll(banners []*HostData) error {
+	for _, banner := range banners {
+		if err := w.Write(banner); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Path returns the path of the file being written.
+func (w *RotatingBannerWriter) Path() string {
+	return w.path
+}
+
+// Flush flushes the compressed data written so far.
+func (w *RotatingBannerWriter) Flush() error {
+	if w.writer == nil {
+		return nil
+	}
+
+	return w.writer.Flush()
+}
+
+// Close finishes the current file.
+func (w *RotatingBannerWriter) Close() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.writer.Close()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil && w.options.OnRotate != nil {
+		w.options.OnRotate(w.path)
+	}
+
+	w.file, w.writer, w.counter = nil, nil, nil
+
+	return err
+}
+
+func (w *RotatingBannerWriter) rotate() error {
+	now := w.options.Now()
+
+	period := now
+	if w.options.Interval > 0 {
+		period = now.Truncate(w.options.Interval)
+	}
+
+	if w.file != nil {
+		expired := w.options.Interval > 0 && !period.Equal(w.period)
+		full := w.options.MaxSize > 0 && w.counter.written >= w.options.MaxSize
+
+		if !expired && !full {
+			return nil
+		}
+
+		if err := w.Close(); err != nil {
+			return err
+		}
+
+		if expired {
+			w.seq = 0
+		} else {
+			w.seq++
+			period = w.period
+		}
+	}
+
+	w.period = period
+
+	return w.open()
+}
+
+// open creates the next file. Existing files (i.e. written before a restart) are never overwritten,
+// the sequence number is increased instead.
+func (w *RotatingBannerWriter) open() error {
+	for {
+		path := filepath.Join(w.options.Dir, w.fileName())
+
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644) //nolint:gosec
+		if os.IsExist(err) {
+			w.seq++
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		w.file, w.path = file, path
+		w.counter = &countingWriter{w: file}
+		w.writer = NewBannerWriter(w.counter, w.options.Compress)
+
+		return nil
+	}
+}
+
+func (w *RotatingBannerWriter) fileName() string {
+	name := w.options.Prefix + "-" + w.period.Format(bannerFileTimeLayout)
+	if w.seq > 0 {
+		name += fmt.Sprintf("-%d", w.seq)
+	}
+
+	name += ".json"
+	if w.options.Compress {
+		name += ".gz"
+	}
+
+	return name
+}
+
+type countingWriter struct {
+	w       io.Writer
+	written int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.written += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,94 @@
+package shodan
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBannerWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	r, err := NewBannerReader(strings.NewReader(`{"port": 80, "cloud": {"provider": "Example"}}`), nil)
+	assert.Nil(t, err)
+
+	banner, err := r.Next()
+	assert.Nil(t, err)
+
+	w := NewBannerWriter(&buf, true)
+	assert.Nil(t, w.WriteAll([]*HostData{banner, {Port: 22}}))
+	assert.Nil(t, w.Close())
+
+	r, err = NewBannerReader(&buf, nil)
+	assert.Nil(t, err)
+
+	banner, err = r.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, 80, banner.Port)
+	assert.JSONEq(t, `{"provider": "Example"}`, string(banner.Extra["cloud"]))
+
+	banner, err = r.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, 22, banner.Port)
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestRotatingBannerWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-shodan")
+	assert.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2021, 1, 26, 8, 15, 0, 0, time.UTC)
+	rotated := make([]string, 0)
+	options := &RotationOptions{
+		Dir:      dir,
+		Compress: true,
+		Interval: time.Hour,
+		MaxSize:  1,
+		Now:      func() time.Time { return now },
+		OnRotate: func(path string) { rotated = append(rotated, filepath.Base(path)) },
+	}
+
+	w := NewRotatingBannerWriter(options)
+	assert.Nil(t, w.Write(&HostData{Port: 80}))
+	assert.Nil(t, w.Flush())
+	assert.Nil(t, w.Write(&HostData{Port: 443}))
+
+	now = now.Add(time.Hour)
+	assert.Nil(t, w.Write(&HostData{Port: 22}))
+	assert.Nil(t, w.Close())
+
+	assert.Equal(t, []string{
+		"banners-20210126T080000.json.gz",
+		"banners-20210126T080000-1.json.gz",
+		"banners-20210126T090000.json.gz",
+	}, rotated)
+
+	f, err := os.Open(filepath.Join(dir, rotated[1]))
+	assert.Nil(t, err)
+
+	defer f.Close()
+
+	r, err := NewBannerReader(f, nil)
+	assert.Nil(t, err)
+
+	banner, err := r.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, 443, banner.Port)
+
+	// existing files are never overwritten
+	w = NewRotatingBannerWriter(options)
+	assert.Nil(t, w.Write(&HostData{Port: 8080}))
+	assert.Equal(t, "banners-20210126T090000-1.json.gz", filepath.Base(w.Path()))
+	assert.Nil(t, w.Close())
+}
@@ -0,0 +1,303 @@
+package shodan
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+const defaultBulkLookupWorkers = 4
+
+// BulkLookupOptions configures BulkLookup.
+type BulkLookupOptions struct {
+	// Workers is the number of concurrent lookups (4 by default). Requests are still throttled
+	// by the client rate limiter, see SetRateLimiter.
+	Workers int
+
+	// Services is passed to every GetServicesForHost call.
+	Services *HostServicesOptions
+
+	// Checkpoint is the path of the file recording the IPs looked up successfully or not found.
+	// The recorded IPs are skipped, so a crashed lookup is resumed by running it with the same checkpoint.
+	// Failed lookups aren't recorded and are retried on resume.
+	Checkpoint string
+}
+
+// BulkResult is the outcome of a single IP lookup.
+type BulkResult struct {
+	// IP is the looked up address or the target that couldn't be expanded.
+	IP string
+
+	// Host is the information about the IP if found.
+	Host *Host
+
+	// NotFound reports that Shodan has no information about the IP.
+	NotFound bool
+
+	// Err is the failure of the lookup or of the target expansion. It's never ErrNotFound.
+	Err error
+}
+
+func (o *BulkLookupOptions) withDefaults() BulkLookupOptions {
+	var options BulkLookupOptions
+	if o != nil {
+		options = *o
+	}
+
+	if options.Workers <= 0 {
+		options.Workers = defaultBulkLookupWorkers
+	}
+
+	return options
+}
+
+// BulkLookup looks up the IPs and CIDR networks, see BulkLookupStream.
+func (c *Client) BulkLookup(ctx context.Context, targets []string, results chan<- *BulkResult,
+	options *BulkLookupOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch := make(chan string)
+
+	go func() {
+		defer close(ch)
+
+		for _, target := range targets {
+			select {
+			case ch <- target:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return c.BulkLookupStream(ctx, ch, results, options)
+}
+
+// BulkLookupStream looks up the IPs and CIDR networks received from the channel until it's closed.
+// Networks are expanded and every address is looked up once. Results are sent in completion order
+// and the results channel is closed when all lookups are done. The returned error is the context error
+// or the checkpoint failure, lookup errors are reported in results.
+func (c *Client) BulkLookupStream(ctx context.Context, targets <-chan string, results chan<- *BulkResult,
+	options *BulkLookupOptions) error {
+	defer close(results)
+
+	opts := options.withDefaults()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	checkpoint, err := openBulkCheckpoint(opts.Checkpoint)
+	if err != nil {
+		return err
+	}
+
+	defer checkpoint.Close()
+
+	ips := make(chan string)
+
+	var wg sync.WaitGroup
+
+	wg.Add(opts.Workers + 1)
+
+	go func() {
+		defer wg.Done()
+		defer close(ips)
+
+		c.expandBulkTargets(ctx, targets, ips, results, checkpoint)
+	}()
+
+	for i := 0; i < opts.Workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for ip := range ips {
+				result := c.lookupBulkHost(ctx, ip, opts.Services)
+				if ctx.Err() != nil {
+					return
+				}
+
+				if !sendBulkResult(ctx, results, result) {
+					return
+				}
+
+				if result.Err == nil {
+					if err := checkpoint.Record(ip); err != nil {
+						cancel()
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := checkpoint.Err(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// expandBulkTargets sends the addresses of the targets which aren't done yet.
+func (c *Client) expandBulkTargets(ctx context.Context, targets <-chan string, ips chan<- string,
+	results chan<- *BulkResult, checkpoint *bulkCheckpoint) {
+	seen := make(map[string]bool)
+
+	for {
+		var (
+			target string
+			ok     bool
+		)
+
+		select {
+		case target, ok = <-targets:
+			if !ok {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+
+		addresses, err := expandNetwork(target)
+		if err != nil {
+			if !sendBulkResult(ctx, results, &BulkResult{IP: target, Err: err}) {
+				return
+			}
+
+			continue
+		}
+
+		for _, address := range addresses {
+			ip := address.String()
+			if seen[ip] || checkpoint.Done(ip) {
+				continue
+			}
+
+			seen[ip] = true
+
+			select {
+			case ips <- ip:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) lookupBulkHost(ctx context.Context, ip string, options *HostServicesOptions) *BulkResult {
+	host, err := c.GetServicesForHost(ctx, ip, options)
+	if errors.Is(err, ErrNotFound) {
+		return &BulkResult{IP: ip, NotFound: true}
+	}
+
+	return &BulkResult{IP: ip, Host: host, Err: err}
+}
+
+func sendBulkResult(ctx context.Context, results chan<- *BulkResult, result *BulkResult) bool {
+	select {
+	case results <- result:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// bulkCheckpoint is the set of finished IPs backed by a file with one IP per line. A zero path disables it.
+type bulkCheckpoint struct {
+	mu   sync.Mutex
+	file *os.File
+	done map[string]bool
+	err  error
+}
+
+func openBulkCheckpoint(path string) (*bulkCheckpoint, error) {
+	checkpoint := &bulkCheckpoint{done: make(map[string]bool)}
+	if path == "" {
+		return checkpoint, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint.file = file
+
+	if err := checkpoint.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+// load reads the finished IPs. Only newline terminated lines are complete: a line truncated by a crash
+// may still parse (i.e. 192.0.2.12 cut to 192.0.2.1), so it's dropped and terminated to not corrupt the next IP.
+func (c *bulkCheckpoint) load() error {
+	reader := bufio.NewReader(c.file)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			if line == "" {
+				return nil
+			}
+
+			_, err = c.file.Write([]byte{'\n'})
+
+			return err
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if ip := net.ParseIP(strings.TrimSuffix(line, "\n")); ip != nil {
+			c.done[ip.String()] = true
+		}
+	}
+}
+
+func (c *bulkCheckpoint) Done(ip string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.done[ip]
+}
+
+func (c *bulkCheckpoint) Record(ip string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.done[ip] = true
+
+	if c.file == nil || c.err != nil {
+		return c.err
+	}
+
+	_, c.err = c.file.WriteString(ip + "\n")
+
+	return c.err
+}
+
+func (c *bulkCheckpoint) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+func (c *bulkCheckpoint) Close() error {
+	if c.file == nil {
+		return nil
+	}
+
+	return c.file.Close()
+}
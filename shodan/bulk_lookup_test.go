@@ -0,0 +1,163 @@
+package shodan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveBulkLookup(mux *http.ServeMux) *[]string {
+	var mu sync.Mutex
+
+	requested := make([]string, 0)
+
+	mux.HandleFunc(hostPath+"/", func(w http.ResponseWriter, r *http.Request) {
+		ip := strings.TrimPrefix(r.URL.Path, hostPath+"/")
+
+		mu.Lock()
+		requested = append(requested, ip)
+		mu.Unlock()
+
+		switch ip {
+		case "192.0.2.1":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": "No information available for that IP."}`)
+		case "192.0.2.2":
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error": "Internal error"}`)
+		default:
+			fmt.Fprintf(w, `{"ip_str": %q, "ports": [80], "data": []}`, ip)
+		}
+	})
+
+	return &requested
+}
+
+func collectBulkResults(results <-chan *BulkResult) map[string]*BulkResult {
+	collected := make(map[string]*BulkResult)
+	for result := range results {
+		collected[result.IP] = result
+	}
+
+	return collected
+}
+
+func TestClient_BulkLookup(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	requested := serveBulkLookup(mux)
+	results := make(chan *BulkResult)
+
+	var collected map[string]*BulkResult
+
+	done := make(chan struct{})
+
+	go func() {
+		collected = collectBulkResults(results)
+		close(done)
+	}()
+
+	err := client.BulkLookup(context.TODO(), []string{"192.0.2.0/30", "192.0.2.3", "bogus"}, results,
+		&BulkLookupOptions{Workers: 2})
+	<-done
+
+	assert.Nil(t, err)
+	assert.Len(t, *requested, 4)
+	assert.Len(t, collected, 5)
+
+	assert.Equal(t, "192.0.2.0", collected["192.0.2.0"].Host.IP.String())
+	assert.Nil(t, collected["192.0.2.0"].Err)
+	assert.True(t, collected["192.0.2.1"].NotFound)
+	assert.Nil(t, collected["192.0.2.1"].Err)
+	assert.True(t, errors.Is(collected["192.0.2.2"].Err, ErrServerError))
+	assert.False(t, collected["192.0.2.2"].NotFound)
+	assert.NotNil(t, collected["192.0.2.3"].Host)
+	assert.NotNil(t, collected["bogus"].Err)
+}
+
+func TestClient_BulkLookupStream_Checkpoint(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	dir, err := ioutil.TempDir("", "go-shodan")
+	assert.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	checkpoint := filepath.Join(dir, "checkpoint")
+	assert.Nil(t, ioutil.WriteFile(checkpoint, []byte("192.0.2.0\n192.0.2.3\n192.0.2."), 0o600))
+
+	requested := serveBulkLookup(mux)
+	targets := make(chan string, 1)
+	targets <- "192.0.2.0/30"
+	close(targets)
+
+	results := make(chan *BulkResult, 4)
+	err = client.BulkLookupStream(context.TODO(), targets, results, &BulkLookupOptions{Checkpoint: checkpoint})
+	assert.Nil(t, err)
+	assert.Len(t, collectBulkResults(results), 2)
+
+	sort.Strings(*requested)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, *requested)
+
+	data, err := ioutil.ReadFile(checkpoint)
+	assert.Nil(t, err)
+	assert.Equal(t, "192.0.2.0\n192.0.2.3\n192.0.2.\n192.0.2.1\n", string(data))
+
+	// the failed lookup is retried
+	results = make(chan *BulkResult, 4)
+	err = client.BulkLookup(context.TODO(), []string{"192.0.2.0/30"}, results, &BulkLookupOptions{Checkpoint: checkpoint})
+	assert.Nil(t, err)
+	assert.Len(t, collectBulkResults(results), 1)
+	assert.Len(t, *requested, 3)
+}
+
+func TestBulkCheckpoint_TruncatedLine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-shodan")
+	assert.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	// 192.0.2.12 truncated by a crash still parses
+	path := filepath.Join(dir, "checkpoint")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("192.0.2.0\n192.0.2.1"), 0o600))
+
+	checkpoint, err := openBulkCheckpoint(path)
+	assert.Nil(t, err)
+	assert.True(t, checkpoint.Done("192.0.2.0"))
+	assert.False(t, checkpoint.Done("192.0.2.1"))
+	assert.Nil(t, checkpoint.Record("192.0.2.12"))
+	assert.Nil(t, checkpoint.Close())
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "192.0.2.0\n192.0.2.1\n192.0.2.12\n", string(data))
+}
+
+func TestClient_BulkLookup_Canceled(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	serveBulkLookup(mux)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := make(chan *BulkResult)
+	err := client.BulkLookup(ctx, []string{"192.0.2.0/30"}, results, nil)
+	assert.Equal(t, context.Canceled, err)
+
+	_, ok := <-results
+	assert.False(t, ok)
+}
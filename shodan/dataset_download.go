@@ -0,0 +1,264 @@
+package shodan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDownloadMaxResumes = 3
+	defaultDownloadMinBackoff = time.Second
+	defaultDownloadMaxBackoff = 30 * time.Second
+)
+
+// DatasetDownloadOptions configures downloading of dataset files.
+type DatasetDownloadOptions struct {
+	// MaxResumes is the number of attempts to resume an interrupted transfer (3 by default, negative disables).
+	MaxResumes int
+
+	// MinBackoff is the delay before the first resume attempt (1 second by default). It's doubled for every next one.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between resume attempts (30 seconds by default).
+	MaxBackoff time.Duration
+
+	// Progress is called every time a chunk is written with the total number of bytes of the file written so far,
+	// including the ones written before resuming.
+	Progress func(file *DatasetFile, written int64)
+}
+
+func (o *DatasetDownloadOptions) withDefaults() DatasetDownloadOptions {
+	var options DatasetDownloadOptions
+	if o != nil {
+		options = *o
+	}
+
+	if options.MaxResumes == 0 {
+		options.MaxResumes = defaultDownloadMaxResumes
+	}
+
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = defaultDownloadMinBackoff
+	}
+
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = defaultDownloadMaxBackoff
+	}
+
+	return options
+}
+
+// DownloadDatasetFile streams the dataset file into the writer resuming interrupted transfers with HTTP range
+// requests. The number of written bytes is verified against DatasetFile.Size. ErrInvalidContentRange is returned
+// if the server resumes from another offset.
+func (c *Client) DownloadDatasetFile(
+	ctx context.Context,
+	file *DatasetFile,
+	w io.Writer,
+	options *DatasetDownloadOptions,
+) (int64, error) {
+	return c.downloadDatasetFile(ctx, file, w, 0, options.withDefaults())
+}
+
+// SaveDatasetFile downloads the dataset file to the path. A partially downloaded file is resumed
+// and a complete one (having DatasetFile.Size) is skipped.
+func (c *Client) SaveDatasetFile(
+	ctx context.Context,
+	file *DatasetFile,
+	path string,
+	options *DatasetDownloadOptions,
+) error {
+	opts := options.withDefaults()
+
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	if file.Size > 0 && offset == file.Size {
+		if opts.Progress != nil {
+			opts.Progress(file, offset)
+		}
+
+		return nil
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if file.Size > 0 && offset > file.Size {
+		offset = 0
+		flags |= os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(path, flags, 0o644) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.downloadDatasetFile(ctx, file, f, offset, opts); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (c *Client) downloadDatasetFile(
+	ctx context.Context,
+	file *DatasetFile,
+	w io.Writer,
+	offset int64,
+	options DatasetDownloadOptions,
+) (int64, error) {
+	written := offset
+
+	var progress func(written int64)
+	if options.Progress != nil {
+		progress = func(written int64) { options.Progress(file, written) }
+	}
+
+	w = &progressWriter{w: w, written: &written, progress: progress}
+
+	for resumes := 0; ; resumes++ {
+		err := c.downloadRange(ctx, file, w, written)
+
+		complete := file.Size <= 0 || written >= file.Size
+		if err == nil && complete {
+			break
+		}
+
+		var interrupted *downloadInterruptedError
+		if err != nil && !errors.As(err, &interrupted) {
+			return written, err
+		}
+
+		if resumes >= options.MaxResumes {
+			if err != nil {
+				return written, err
+			}
+
+			break
+		}
+
+		backoff := jitter(exponentialBackoff(options.MinBackoff, options.MaxBackoff, resumes+1))
+		if err := sleepContext(ctx, backoff); err != nil {
+			return written, err
+		}
+	}
+
+	if file.Size > 0 && written != file.Size {
+		return written, fmt.Errorf("%w: %d of %d bytes", ErrDownloadIncomplete, written, file.Size)
+	}
+
+	return written, nil
+}
+
+// downloadRange writes the file starting from the offset. Failures of reading the body are returned
+// as *downloadInterruptedError, write failures are returned as is.
+func (c *Client) downloadRange(ctx context.Context, file *DatasetFile, w io.Writer, offset int64) error {
+	req, err := http.NewRequest("GET", file.URL.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	body := &interruptibleReader{r: resp.Body}
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		contentRange := resp.Header.Get("Content-Range")
+		if start, ok := contentRangeStart(contentRange); !ok || start != offset {
+			return fmt.Errorf("%w: requested %d, got %q", ErrInvalidContentRange, offset, contentRange)
+		}
+	case resp.StatusCode == http.StatusOK:
+		// the range is ignored, skip the bytes already written
+		if _, err := io.CopyN(ioutil.Discard, body, offset); err != nil {
+			return err
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		return nil
+	default:
+		return getErrorFromResponse(resp)
+	}
+
+	_, err = io.Copy(w, body)
+
+	return err
+}
+
+// contentRangeStart returns the first byte position of the "bytes first-last/length" content range.
+func contentRangeStart(contentRange string) (int64, bool) {
+	if !strings.HasPrefix(contentRange, "bytes ") {
+		return 0, false
+	}
+
+	i := strings.IndexByte(contentRange, '-')
+	if i < 0 {
+		return 0, false
+	}
+
+	start, err := strconv.ParseInt(contentRange[len("bytes "):i], 10, 64)
+
+	return start, err == nil
+}
+
+type downloadInterruptedError struct {
+	err error
+}
+
+func (e *downloadInterruptedError) Error() string {
+	return "download interrupted: " + e.err.Error()
+}
+
+func (e *downloadInterruptedError) Unwrap() error {
+	return e.err
+}
+
+// interruptibleReader marks read errors (i.e. a dropped connection) as resumable.
+type interruptibleReader struct {
+	r io.Reader
+}
+
+func (r *interruptibleReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if err != nil && err != io.EOF {
+		err = &downloadInterruptedError{err: err}
+	}
+
+	return n, err
+}
+
+// progressWriter counts the written bytes and reports the total.
+type progressWriter struct {
+	w        io.Writer
+	written  *int64
+	progress func(written int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	*p.written += int64(n)
+
+	if n > 0 && p.progress != nil {
+		p.progress(*p.written)
+	}
+
+	return n, err
+}
@@ -0,0 +1,183 @@
+package shodan
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDatasetContent = "{\"port\": 80}\n{\"port\": 443}\n{\"port\": 22}\n"
+
+// serveFlakyDataset serves the content dropping the connection in the middle of the first response.
+func serveFlakyDataset(t *testing.T, mux *http.ServeMux, path string) *[]string {
+	ranges := make([]string, 0)
+
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.URL.Query().Get("key"))
+		ranges = append(ranges, r.Header.Get("Range"))
+
+		if len(ranges) == 1 && r.Header.Get("Range") == "" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(testDatasetContent)))
+			w.Write([]byte(testDatasetContent[:10]))
+
+			return
+		}
+
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(testDatasetContent))
+	})
+
+	return &ranges
+}
+
+func newTestDatasetFile(t *testing.T, client *Client, path string) *DatasetFile {
+	u, err := url.Parse(client.BaseURL + path)
+	assert.Nil(t, err)
+
+	return &DatasetFile{URL: u, Name: "file.json", Size: int64(len(testDatasetContent))}
+}
+
+func TestClient_DownloadDatasetFile(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	ranges := serveFlakyDataset(t, mux, "/download/file.json")
+	file := newTestDatasetFile(t, client, "/download/file.json")
+
+	var (
+		buf      bytes.Buffer
+		progress []int64
+	)
+
+	options := &DatasetDownloadOptions{
+		MinBackoff: time.Millisecond,
+		Progress: func(f *DatasetFile, written int64) {
+			assert.Equal(t, file, f)
+			progress = append(progress, written)
+		},
+	}
+
+	n, err := client.DownloadDatasetFile(context.TODO(), file, &buf, options)
+
+	assert.Nil(t, err)
+	assert.Equal(t, file.Size, n)
+	assert.Equal(t, testDatasetContent, buf.String())
+	assert.Equal(t, []string{"", "bytes=10-"}, *ranges)
+	assert.Equal(t, []int64{10, file.Size}, progress)
+}
+
+func TestClient_DownloadDatasetFileErrors(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	serveFlakyDataset(t, mux, "/download/file.json")
+	mux.HandleFunc("/download/missing.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	file := newTestDatasetFile(t, client, "/download/file.json")
+	_, err := client.DownloadDatasetFile(context.TODO(), file, ioutil.Discard, &DatasetDownloadOptions{MaxResumes: -1})
+	assert.NotNil(t, err)
+
+	file = newTestDatasetFile(t, client, "/download/file.json")
+	file.Size++
+	_, err = client.DownloadDatasetFile(context.TODO(), file, ioutil.Discard, &DatasetDownloadOptions{MaxResumes: -1})
+	assert.True(t, errors.Is(err, ErrDownloadIncomplete))
+
+	file = newTestDatasetFile(t, client, "/download/missing.json")
+	_, err = client.DownloadDatasetFile(context.TODO(), file, ioutil.Discard, nil)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestClient_DownloadDatasetFileContentRange(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	requests := 0
+
+	// the range is served from the wrong offset after the connection drops
+	mux.HandleFunc("/download/file.json", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.Header.Get("Range") == "" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(testDatasetContent)))
+			w.Write([]byte(testDatasetContent[:10]))
+
+			return
+		}
+
+		w.Header().Set("Content-Range", "bytes 0-"+strconv.Itoa(len(testDatasetContent)-1)+"/*")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte(testDatasetContent))
+	})
+
+	var buf bytes.Buffer
+
+	file := newTestDatasetFile(t, client, "/download/file.json")
+	n, err := client.DownloadDatasetFile(context.TODO(), file, &buf, &DatasetDownloadOptions{MinBackoff: time.Millisecond})
+
+	assert.True(t, errors.Is(err, ErrInvalidContentRange))
+	assert.Equal(t, int64(10), n)
+	assert.Equal(t, testDatasetContent[:10], buf.String())
+	assert.Equal(t, 2, requests)
+}
+
+func TestContentRangeStart(t *testing.T) {
+	start, ok := contentRangeStart("bytes 10-42/43")
+	assert.True(t, ok)
+	assert.Equal(t, int64(10), start)
+
+	_, ok = contentRangeStart("bytes */43")
+	assert.False(t, ok)
+
+	_, ok = contentRangeStart("")
+	assert.False(t, ok)
+}
+
+func TestClient_SaveDatasetFile(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	dir, err := ioutil.TempDir("", "go-shodan")
+	assert.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	requests := 0
+	mux.HandleFunc("/download/file.json", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(testDatasetContent))
+	})
+
+	file := newTestDatasetFile(t, client, "/download/file.json")
+	path := filepath.Join(dir, file.Name)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte(testDatasetContent[:20]), 0o600))
+	assert.Nil(t, client.SaveDatasetFile(context.TODO(), file, path, nil))
+
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, testDatasetContent, string(content))
+	assert.Equal(t, 1, requests)
+
+	assert.Nil(t, client.SaveDatasetFile(context.TODO(), file, path, nil))
+	assert.Equal(t, 1, requests)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte(testDatasetContent+"garbage"), 0o600))
+	assert.Nil(t, client.SaveDatasetFile(context.TODO(), file, path, nil))
+
+	content, err = ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, testDatasetContent, string(content))
+	assert.Equal(t, 2, requests)
+}
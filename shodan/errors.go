@@ -3,6 +3,7 @@ package shodan
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -12,22 +13,147 @@ var (
 	// ErrInvalidQuery is returned when query is not valid.
 	ErrInvalidQuery = errors.New("query is invalid")
 
+	// ErrInvalidFacet is returned when facet is unknown or malformed.
+	ErrInvalidFacet = errors.New("facet is invalid")
+
+	// ErrInvalidScanRequest is returned when scan request has invalid networks or services.
+	ErrInvalidScanRequest = errors.New("scan request is invalid")
+
+	// ErrInvalidAlert is returned when desired alerts are malformed or can't be matched unambiguously.
+	ErrInvalidAlert = errors.New("alert is invalid")
+
+	// ErrInvalidNotifier is returned when notifier has unknown provider or misses required arguments.
+	ErrInvalidNotifier = errors.New("notifier is invalid")
+
+	// ErrInvalidSignature is returned when the signature of the alert notification doesn't match.
+	ErrInvalidSignature = errors.New("signature is invalid")
+
+	// ErrInvalidNetwork is returned when a network is neither IP nor CIDR.
+	ErrInvalidNetwork = errors.New("network is invalid")
+
+	// ErrNetworkTooLarge is returned when a network is too large to be expanded into addresses.
+	ErrNetworkTooLarge = errors.New("network is too large")
+
+	// ErrDownloadIncomplete is returned when the downloaded file size doesn't match the expected one.
+	ErrDownloadIncomplete = errors.New("download is incomplete")
+
+	// ErrInvalidContentRange is returned when the partial content doesn't start at the requested offset.
+	ErrInvalidContentRange = errors.New("content range doesn't match the requested offset")
+
 	// ErrBodyRead is returned when response's body cannot be read.
 	ErrBodyRead = errors.New("could not read error response")
+
+	// ErrReaderClosed is returned when reading from a closed reader.
+	ErrReaderClosed = errors.New("reader is closed")
+
+	// ErrStreamDisconnected is reported when the stream connection ends without an error.
+	ErrStreamDisconnected = errors.New("stream disconnected")
+
+	// ErrIteratorDone is returned by iterators when there are no more results.
+	ErrIteratorDone = errors.New("no more results")
+
+	// ErrUnauthorized is matched by API errors caused by a missing or invalid API key
+	// or by an API plan that doesn't allow the method.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrNotFound is matched by API errors for missing resources, i.e. a host without information.
+	ErrNotFound = errors.New("not found")
+
+	// ErrRateLimited is matched by API errors caused by exceeding the request rate.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrNoCredits is matched by API errors caused by running out of query or scan credits.
+	ErrNoCredits = errors.New("insufficient credits")
+
+	// ErrServerError is matched by API errors caused by Shodan internal failures (5xx).
+	ErrServerError = errors.New("server error")
 )
 
-func getErrorFromResponse(r *http.Response) error {
-	errorResponse := new(struct {
-		Error string `json:"error"`
-	})
-	message, err := ioutil.ReadAll(r.Body)
-	if err == nil {
-		if err := json.Unmarshal(message, errorResponse); err == nil {
-			return errors.New(errorResponse.Error)
-		}
+// APIError is returned when Shodan responds with non-200 status code.
+// It can be matched against ErrUnauthorized, ErrNotFound, ErrRateLimited,
+// ErrNoCredits and ErrServerError using errors.Is.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Endpoint is the method and path of the failed request (without the API key).
+	Endpoint string
+
+	// Body is the raw response body.
+	Body []byte
 
-		return errors.New(strings.TrimSpace(string(message)))
+	// Message is the error message parsed from the body.
+	Message string
+}
+
+// Error returns the message Shodan responded with.
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return e.Message
 	}
 
-	return ErrBodyRead
+	return fmt.Sprintf("%s: unexpected status code %d", e.Endpoint, e.StatusCode)
+}
+
+// Is reports whether the error matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	return target != nil && e.kind() == target
+}
+
+func (e *APIError) kind() error {
+	message := strings.ToLower(e.Message)
+	if strings.Contains(message, "credits") {
+		return ErrNoCredits
+	}
+
+	switch {
+	case e.StatusCode == http.StatusUnauthorized, e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusPaymentRequired:
+		return ErrNoCredits
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServerError
+	}
+
+	return nil
+}
+
+func newAPIError(r *http.Response, body []byte, message string) *APIError {
+	apiErr := &APIError{
+		StatusCode: r.StatusCode,
+		Body:       body,
+		Message:    message,
+	}
+
+	if r.Request != nil && r.Request.URL != nil {
+		apiErr.Endpoint = r.Request.Method + " " + r.Request.URL.Path
+	}
+
+	return apiErr
+}
+
+// readAPIError reads the response body and builds *APIError taking the message
+// from the JSON field named by key or falling back to the whole body.
+func readAPIError(r *http.Response, key string) error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return ErrBodyRead
+	}
+
+	var errorResponse map[string]interface{}
+	if err := json.Unmarshal(body, &errorResponse); err == nil {
+		if message, ok := errorResponse[key].(string); ok {
+			return newAPIError(r, body, message)
+		}
+	}
+
+	return newAPIError(r, body, strings.TrimSpace(string(body)))
+}
+
+func getErrorFromResponse(r *http.Response) error {
+	return readAPIError(r, "error")
 }
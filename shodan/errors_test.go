@@ -0,0 +1,106 @@
+package shodan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	testCases := []struct {
+		statusCode int
+		message    string
+		expected   error
+	}{
+		{http.StatusUnauthorized, "Please provide a valid API key", ErrUnauthorized},
+		{http.StatusForbidden, "Access denied", ErrUnauthorized},
+		{http.StatusForbidden, "Insufficient query credits, please upgrade your API plan", ErrNoCredits},
+		{http.StatusPaymentRequired, "", ErrNoCredits},
+		{http.StatusNotFound, "No information available for that IP.", ErrNotFound},
+		{http.StatusTooManyRequests, "Rate limit reached", ErrRateLimited},
+		{http.StatusBadGateway, "", ErrServerError},
+	}
+
+	sentinels := []error{ErrUnauthorized, ErrNotFound, ErrRateLimited, ErrNoCredits, ErrServerError}
+
+	for _, testCase := range testCases {
+		err := fmt.Errorf("wrapped: %w", &APIError{StatusCode: testCase.statusCode, Message: testCase.message})
+
+		for _, sentinel := range sentinels {
+			assert.Equal(t, sentinel == testCase.expected, errors.Is(err, sentinel), testCase.message)
+		}
+	}
+}
+
+func TestClient_Do_APIError(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(hostPath+"/127.0.0.1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": "No information available for that IP."}`)
+	})
+
+	_, err := client.GetServicesForHost(context.TODO(), "127.0.0.1", nil)
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "GET "+hostPath+"/127.0.0.1", apiErr.Endpoint)
+	assert.Equal(t, "No information available for that IP.", apiErr.Message)
+	assert.Equal(t, "No information available for that IP.", err.Error())
+	assert.NotContains(t, apiErr.Endpoint, testClientToken)
+}
+
+func TestClient_Do_APIErrorPlainText(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(infoPath, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, "401 Unauthorized\n")
+	})
+
+	_, err := client.GetAPIInfo(context.TODO())
+
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.Equal(t, "401 Unauthorized", err.Error())
+}
+
+func TestClient_DoStream_APIError(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(bannersPath, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error": "Rate limit reached"}`)
+	})
+
+	err := client.GetBanners(context.TODO(), make(chan *HostData))
+
+	assert.True(t, errors.Is(err, ErrRateLimited))
+}
+
+func TestClient_GeoNet_APIError(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(fmt.Sprintf(geonetPingPath, "127.0.0.1"), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"detail": "Internal error"}`)
+	})
+
+	_, err := client.GeoPing(context.TODO(), net.ParseIP("127.0.0.1"))
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.True(t, errors.Is(err, ErrServerError))
+	assert.Equal(t, "Internal error", apiErr.Message)
+	assert.Equal(t, []byte(`{"detail": "Internal error"}`), apiErr.Body)
+}
@@ -1,7 +1,203 @@
 package shodan
 
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 // Facet is a property to get summary information on.
 type Facet struct {
 	Count int    `json:"count"`
 	Value string `json:"value"`
 }
+
+// UnmarshalJSON implements Unmarshaler interface to accept numeric and boolean values
+// (i.e. of port facet) casting them to string.
+func (f *Facet) UnmarshalJSON(data []byte) error {
+	type Alias Facet
+
+	ff := &struct {
+		*Alias
+		Value json.RawMessage `json:"value"`
+	}{
+		Alias: (*Alias)(f),
+	}
+
+	if err := json.Unmarshal(data, ff); err != nil {
+		return err
+	}
+
+	f.Value = ""
+
+	switch {
+	case len(ff.Value) == 0 || bytes.Equal(ff.Value, []byte("null")):
+	case ff.Value[0] == '"':
+		return json.Unmarshal(ff.Value, &f.Value)
+	default:
+		f.Value = string(ff.Value)
+	}
+
+	return nil
+}
+
+// Int returns the value of numeric facet (i.e. port).
+func (f *Facet) Int() (int, error) {
+	return strconv.Atoi(f.Value)
+}
+
+// IntFacet is a facet with numeric value.
+type IntFacet struct {
+	Count int
+	Value int
+}
+
+// IntFacets converts the facets of numeric property to IntFacet.
+func IntFacets(facets []*Facet) ([]*IntFacet, error) {
+	intFacets := make([]*IntFacet, 0, len(facets))
+
+	for _, facet := range facets {
+		value, err := facet.Int()
+		if err != nil {
+			return nil, err
+		}
+
+		intFacets = append(intFacets, &IntFacet{Count: facet.Count, Value: value})
+	}
+
+	return intFacets, nil
+}
+
+// SortFacets returns the facets sorted by count in descending order. Facets with the same count
+// are sorted by value.
+func SortFacets(facets []*Facet) []*Facet {
+	sorted := make([]*Facet, len(facets))
+	copy(sorted, facets)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+
+		return sorted[i].Value < sorted[j].Value
+	})
+
+	return sorted
+}
+
+// MergeFacets sums the counts of the same values across several results (i.e. of different queries).
+// The merged facets are sorted with SortFacets.
+func MergeFacets(results ...map[string][]*Facet) map[string][]*Facet {
+	counts := make(map[string]map[string]int)
+
+	for _, result := range results {
+		for name, facets := range result {
+			if _, ok := counts[name]; !ok {
+				counts[name] = make(map[string]int)
+			}
+
+			for _, facet := range facets {
+				counts[name][facet.Value] += facet.Count
+			}
+		}
+	}
+
+	merged := make(map[string][]*Facet, len(counts))
+	for name, values := range counts {
+		facets := make([]*Facet, 0, len(values))
+		for value, count := range values {
+			facets = append(facets, &Facet{Count: count, Value: value})
+		}
+
+		merged[name] = SortFacets(facets)
+	}
+
+	return merged
+}
+
+// FacetSpec requests summary information on a property. Count is the number of top values
+// to return (Shodan default is used if zero).
+type FacetSpec struct {
+	Name  string
+	Count int
+}
+
+// String returns the spec in name:count form.
+func (s FacetSpec) String() string {
+	if s.Count > 0 {
+		return s.Name + ":" + strconv.Itoa(s.Count)
+	}
+
+	return s.Name
+}
+
+// JoinFacets returns the specs in the form accepted by HostQueryOptions.Facets.
+func JoinFacets(specs ...FacetSpec) string {
+	parts := make([]string, len(specs))
+	for i, spec := range specs {
+		parts[i] = spec.String()
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// ParseFacets parses comma-separated facets, i.e. "country:50,port".
+func ParseFacets(s string) ([]FacetSpec, error) {
+	specs := make([]FacetSpec, 0)
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		spec := FacetSpec{Name: part}
+
+		if i := strings.LastIndex(part, ":"); i >= 0 {
+			count, err := strconv.Atoi(part[i+1:])
+			if err != nil || count < 1 || i == 0 {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidFacet, part)
+			}
+
+			spec = FacetSpec{Name: part[:i], Count: count}
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// ValidateFacets checks the specs against the facets returned by GetFacets.
+func (c *Client) ValidateFacets(ctx context.Context, specs ...FacetSpec) error {
+	facets, err := c.GetFacets(ctx)
+	if err != nil {
+		return err
+	}
+
+	return validateFacets(facets, specs)
+}
+
+func validateFacets(available []string, specs []FacetSpec) error {
+	known := make(map[string]bool, len(available))
+	for _, name := range available {
+		known[name] = true
+	}
+
+	unknown := make([]string, 0)
+	for _, spec := range specs {
+		if !known[spec.Name] || spec.Count < 0 {
+			unknown = append(unknown, spec.String())
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidFacet, strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
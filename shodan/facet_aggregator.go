@@ -0,0 +1,100 @@
+package shodan
+
+// defaultFacetCount is the number of top values returned by Shodan if the facet count isn't set.
+const defaultFacetCount = 5
+
+// FacetAggregator computes facets over banners locally, i.e. over a downloaded dataset or a captured stream.
+// The results have the same form as HostMatch.Facets: top values sorted by count (5 if the spec count
+// is zero like the API does). Every banner counts once per distinct value.
+type FacetAggregator struct {
+	specs  []FacetSpec
+	counts map[string]map[string]int
+	total  int
+}
+
+// NewFacetAggregator creates an aggregator of the facets. ErrInvalidFacet is returned for facets
+// that can't be computed locally, see LocalFacets.
+func NewFacetAggregator(specs ...FacetSpec) (*FacetAggregator, error) {
+	if err := validateFacets(LocalFacets(), specs); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]map[string]int, len(specs))
+	for _, spec := range specs {
+		counts[spec.Name] = make(map[string]int)
+	}
+
+	return &FacetAggregator{specs: specs, counts: counts}, nil
+}
+
+// LocalFacets returns the sorted names of the facets FacetAggregator can compute, the fields
+// supported by HostData.FieldValues.
+func LocalFacets() []string {
+	return HostDataFieldNames()
+}
+
+// AggregateFacets computes the facets over the banners.
+func AggregateFacets(banners []*HostData, specs ...FacetSpec) (map[string][]*Facet, error) {
+	aggregator, err := NewFacetAggregator(specs...)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregator.AddAll(banners)
+
+	return aggregator.Facets(), nil
+}
+
+// Add counts the banner.
+func (a *FacetAggregator) Add(banner *HostData) {
+	a.total++
+
+	for name, counts := range a.counts {
+		seen := make(map[string]bool)
+
+		values, _ := banner.FieldValues(name)
+		for _, value := range values {
+			if !seen[value] {
+				seen[value] = true
+				counts[value]++
+			}
+		}
+	}
+}
+
+// AddAll counts the banners, i.e. HostMatch.Matches or Host.Data.
+func (a *FacetAggregator) AddAll(banners []*HostData) {
+	for _, banner := range banners {
+		a.Add(banner)
+	}
+}
+
+// Total returns the number of counted banners like HostMatch.Total.
+func (a *FacetAggregator) Total() int {
+	return a.total
+}
+
+// Facets returns the top values of every facet.
+func (a *FacetAggregator) Facets() map[string][]*Facet {
+	facets := make(map[string][]*Facet, len(a.specs))
+
+	for _, spec := range a.specs {
+		values := make([]*Facet, 0, len(a.counts[spec.Name]))
+		for value, count := range a.counts[spec.Name] {
+			values = append(values, &Facet{Count: count, Value: value})
+		}
+
+		limit := spec.Count
+		if limit == 0 {
+			limit = defaultFacetCount
+		}
+
+		if values = SortFacets(values); len(values) > limit {
+			values = values[:limit]
+		}
+
+		facets[spec.Name] = values
+	}
+
+	return facets
+}
@@ -0,0 +1,63 @@
+package shodan
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFacetAggregator(t *testing.T) {
+	banners := make([]*HostData, 0)
+	assert.Nil(t, json.Unmarshal([]byte(`[
+		{"port": 443, "location": {"country_code": "US"}, "tags": ["cloud"], "hostnames": ["a.example.com", "a.example.com"]},
+		{"port": 443, "location": {"country_code": "DE"}, "tags": ["cloud", "cdn"]},
+		{"port": 80, "location": {"country_code": "US"}, "vulns": {"CVE-2021-23017": {}}},
+		{"port": 22, "location": {"country_code": "NL"}},
+		{"port": 21, "location": {"country_code": "FR"}},
+		{"port": 8080},
+		{"port": 8443}
+	]`), &banners))
+
+	aggregator, err := NewFacetAggregator(FacetSpec{Name: "port"}, FacetSpec{Name: "country", Count: 2},
+		FacetSpec{Name: "tag"}, FacetSpec{Name: "hostname"}, FacetSpec{Name: "vuln"})
+	assert.Nil(t, err)
+
+	aggregator.AddAll(banners)
+	facets := aggregator.Facets()
+
+	assert.Equal(t, 7, aggregator.Total())
+	assert.Equal(t, []*Facet{
+		{Count: 2, Value: "443"},
+		{Count: 1, Value: "21"},
+		{Count: 1, Value: "22"},
+		{Count: 1, Value: "80"},
+		{Count: 1, Value: "8080"},
+	}, facets["port"])
+	assert.Equal(t, []*Facet{{Count: 2, Value: "US"}, {Count: 1, Value: "DE"}}, facets["country"])
+	assert.Equal(t, []*Facet{{Count: 2, Value: "cloud"}, {Count: 1, Value: "cdn"}}, facets["tag"])
+	assert.Equal(t, []*Facet{{Count: 1, Value: "a.example.com"}}, facets["hostname"])
+	assert.Equal(t, []*Facet{{Count: 1, Value: "CVE-2021-23017"}}, facets["vuln"])
+
+	ports, err := IntFacets(facets["port"])
+	assert.Nil(t, err)
+	assert.Equal(t, 443, ports[0].Value)
+}
+
+func TestAggregateFacets(t *testing.T) {
+	facets, err := AggregateFacets([]*HostData{{Product: "nginx"}, {Product: "nginx"}, {}}, FacetSpec{Name: "product"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]*Facet{"product": {{Count: 2, Value: "nginx"}}}, facets)
+
+	// the title of a banner without HTTP module is counted like the evaluator matches it
+	banners := []*HostData{{HTTP: &HostHTTP{Title: "Welcome"}}, {Title: "Welcome"}}
+	facets, err = AggregateFacets(banners, FacetSpec{Name: "http.title"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]*Facet{"http.title": {{Count: 2, Value: "Welcome"}}}, facets)
+
+	_, err = AggregateFacets(nil, FacetSpec{Name: "screenshot.label"})
+	assert.True(t, errors.Is(err, ErrInvalidFacet))
+}
@@ -0,0 +1,76 @@
+package shodan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFacet_UnmarshalJSON(t *testing.T) {
+	payload := []byte(`{"port": [{"count": 10, "value": 80}, {"count": 5, "value": 443}],
+		"country": [{"count": 7, "value": "US"}], "has_screenshot": [{"count": 2, "value": true}]}`)
+
+	var facets map[string][]*Facet
+	assert.Nil(t, json.Unmarshal(payload, &facets))
+
+	assert.Equal(t, []*Facet{{Count: 10, Value: "80"}, {Count: 5, Value: "443"}}, facets["port"])
+	assert.Equal(t, []*Facet{{Count: 7, Value: "US"}}, facets["country"])
+	assert.Equal(t, []*Facet{{Count: 2, Value: "true"}}, facets["has_screenshot"])
+
+	ports, err := IntFacets(facets["port"])
+	assert.Nil(t, err)
+	assert.Equal(t, []*IntFacet{{Count: 10, Value: 80}, {Count: 5, Value: 443}}, ports)
+
+	_, err = IntFacets(facets["country"])
+	assert.NotNil(t, err)
+}
+
+func TestSortFacets(t *testing.T) {
+	facets := []*Facet{{Count: 1, Value: "b"}, {Count: 3, Value: "c"}, {Count: 1, Value: "a"}}
+
+	assert.Equal(t, []*Facet{{Count: 3, Value: "c"}, {Count: 1, Value: "a"}, {Count: 1, Value: "b"}}, SortFacets(facets))
+	assert.Equal(t, "b", facets[0].Value)
+}
+
+func TestMergeFacets(t *testing.T) {
+	merged := MergeFacets(
+		map[string][]*Facet{"port": {{Count: 10, Value: "80"}, {Count: 5, Value: "443"}}},
+		map[string][]*Facet{"port": {{Count: 7, Value: "443"}}, "country": {{Count: 1, Value: "US"}}},
+	)
+
+	assert.Equal(t, map[string][]*Facet{
+		"port":    {{Count: 12, Value: "443"}, {Count: 10, Value: "80"}},
+		"country": {{Count: 1, Value: "US"}},
+	}, merged)
+}
+
+func TestParseFacets(t *testing.T) {
+	specs, err := ParseFacets("country:50, port,ssl.version:3")
+	assert.Nil(t, err)
+	assert.Equal(t, []FacetSpec{{Name: "country", Count: 50}, {Name: "port"}, {Name: "ssl.version", Count: 3}}, specs)
+	assert.Equal(t, "country:50,port,ssl.version:3", JoinFacets(specs...))
+
+	for _, invalid := range []string{"country:x", "port:0", ":5"} {
+		_, err = ParseFacets(invalid)
+		assert.True(t, errors.Is(err, ErrInvalidFacet), invalid)
+	}
+}
+
+func TestClient_ValidateFacets(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(hostSearchFacetsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`["country", "port", "org"]`))
+	})
+
+	assert.Nil(t, client.ValidateFacets(context.TODO(), FacetSpec{Name: "country", Count: 50}, FacetSpec{Name: "port"}))
+
+	err := client.ValidateFacets(context.TODO(), FacetSpec{Name: "country"}, FacetSpec{Name: "colour", Count: 3})
+	assert.True(t, errors.Is(err, ErrInvalidFacet))
+	assert.Contains(t, err.Error(), "colour:3")
+}
@@ -2,10 +2,7 @@ package shodan
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
@@ -61,20 +58,7 @@ type DNSQueryOptions struct {
 }
 
 func getGeoNetErrorFromResponse(r *http.Response) error {
-	errorResponse := new(struct {
-		Error string `json:"detail"`
-	})
-
-	message, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		return ErrBodyRead
-	}
-
-	if err := json.Unmarshal(message, errorResponse); err != nil {
-		return err
-	}
-
-	return errors.New(errorResponse.Error)
+	return readAPIError(r, "detail")
 }
 
 // NewGeoNetRequest prepares new request to geonet shodan api.
@@ -1,10 +1,18 @@
 package shodan
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
 	"strconv"
+	"strings"
 )
 
+// maxNetworkSize limits the number of addresses expandNetwork produces.
+const maxNetworkSize = 1 << 16
+
 type genericSuccessResponse struct {
 	Success bool `json:"success"`
 }
@@ -35,3 +43,87 @@ func (v *IntString) UnmarshalJSON(b []byte) error {
 func (v *IntString) String() string {
 	return string(*v)
 }
+
+type jsonField struct {
+	index     int
+	omitEmpty bool
+}
+
+// jsonFields maps json keys of the struct type to its fields.
+func jsonFields(t reflect.Type) map[string]jsonField {
+	fields := make(map[string]jsonField)
+
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		field := jsonField{index: i}
+
+		for _, option := range parts[1:] {
+			if option == "omitempty" {
+				field.omitEmpty = true
+			}
+		}
+
+		fields[parts[0]] = field
+	}
+
+	return fields
+}
+
+// expandNetwork returns all addresses of IP or CIDR network.
+func expandNetwork(network string) ([]net.IP, error) {
+	if ip := net.ParseIP(network); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	ip, ipNet, err := net.ParseCIDR(network)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones > 16 {
+		return nil, fmt.Errorf("%w: %s has more than %d addresses", ErrNetworkTooLarge, network, maxNetworkSize)
+	}
+
+	ips := make([]net.IP, 0, 1<<uint(bits-ones))
+	for ip = ip.Mask(ipNet.Mask); ipNet.Contains(ip); ip = nextIP(ip) {
+		ips = append(ips, ip)
+	}
+
+	return ips, nil
+}
+
+// nextIP returns a copy of the address incremented by one.
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+
+	return next
+}
+
+// CompareIPs compares the textual addresses numerically, i.e. 10.0.0.9 sorts before 10.0.0.10.
+// Malformed addresses are compared as strings.
+func CompareIPs(a, b string) int {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return strings.Compare(a, b)
+	}
+
+	if a4, b4 := ipA.To4(), ipB.To4(); a4 != nil && b4 != nil {
+		ipA, ipB = a4, b4
+	}
+
+	return bytes.Compare(ipA, ipB)
+}
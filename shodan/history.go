@@ -0,0 +1,242 @@
+package shodan
+
+import (
+	"context"
+	"net"
+	"sort"
+	"time"
+)
+
+// HostHistoryOptions configures grouping of historical banners.
+type HostHistoryOptions struct {
+	// Resolution truncates banner timestamps before grouping, i.e. 24 * time.Hour makes daily snapshots
+	// and a service missing from a period is considered closed.
+	//
+	// If zero, banners are grouped by the exact timestamp. Since every banner has its own timestamp,
+	// a snapshot then carries forward the latest banners of the services seen before it, so services
+	// are never reported as closed.
+	Resolution time.Duration
+}
+
+// HostSnapshot is the set of services observed at the same time (or within the same resolution period).
+type HostSnapshot struct {
+	Timestamp time.Time
+	Services  map[ServiceKey]*HostData
+}
+
+// HostHistory is the timeline of the host snapshots in chronological order.
+type HostHistory struct {
+	IP        net.IP
+	Snapshots []*HostSnapshot
+}
+
+// ServiceChange is a change of a service property between two snapshots.
+type ServiceChange struct {
+	Service ServiceKey
+	Field   string
+	From    string
+	To      string
+}
+
+// VulnChange is a vulnerability found on a service.
+type VulnChange struct {
+	CVE     string
+	Service ServiceKey
+	*HostVulnerability
+}
+
+// HostDiff describes the changes between two snapshots.
+type HostDiff struct {
+	From time.Time
+	To   time.Time
+
+	// Opened are services present only in the later snapshot.
+	Opened []*HostData
+
+	// Closed are services present only in the earlier snapshot.
+	Closed []*HostData
+
+	// Changed are product and version changes of services present in both snapshots.
+	Changed []*ServiceChange
+
+	// CertificateRotations are certificate fingerprint changes of services present in both snapshots.
+	CertificateRotations []*ServiceChange
+
+	// NewVulns are vulnerabilities of the later snapshot the earlier one wasn't affected by.
+	NewVulns []*VulnChange
+}
+
+// GetHostHistory returns the timeline of the host built from all historical banners.
+func (c *Client) GetHostHistory(ctx context.Context, ip string, options *HostHistoryOptions) (*HostHistory, error) {
+	host, err := c.GetServicesForHost(ctx, ip, &HostServicesOptions{History: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHostHistory(host, options)
+}
+
+// NewHostHistory groups the banners of the host into snapshots. If several banners of the same service fall
+// into one snapshot the latest one is kept.
+func NewHostHistory(host *Host, options *HostHistoryOptions) (*HostHistory, error) {
+	var resolution time.Duration
+	if options != nil {
+		resolution = options.Resolution
+	}
+
+	snapshots := make(map[time.Time]*HostSnapshot)
+	timestamps := make(map[*HostData]time.Time)
+
+	for _, data := range host.Data {
+		t, err := data.Time()
+		if err != nil {
+			return nil, err
+		}
+
+		timestamps[data] = t
+		if resolution > 0 {
+			t = t.Truncate(resolution)
+		}
+
+		snapshot, ok := snapshots[t]
+		if !ok {
+			snapshot = &HostSnapshot{Timestamp: t, Services: make(map[ServiceKey]*HostData)}
+			snapshots[t] = snapshot
+		}
+
+		key := data.Service()
+		if known, ok := snapshot.Services[key]; !ok || timestamps[known].Before(timestamps[data]) {
+			snapshot.Services[key] = data
+		}
+	}
+
+	history := &HostHistory{IP: host.IP, Snapshots: make([]*HostSnapshot, 0, len(snapshots))}
+	for _, snapshot := range snapshots {
+		history.Snapshots = append(history.Snapshots, snapshot)
+	}
+
+	sort.Slice(history.Snapshots, func(i, j int) bool {
+		return history.Snapshots[i].Timestamp.Before(history.Snapshots[j].Timestamp)
+	})
+
+	if resolution == 0 {
+		for i := 1; i < len(history.Snapshots); i++ {
+			for key, data := range history.Snapshots[i-1].Services {
+				if _, ok := history.Snapshots[i].Services[key]; !ok {
+					history.Snapshots[i].Services[key] = data
+				}
+			}
+		}
+	}
+
+	return history, nil
+}
+
+// Diff compares snapshots with indexes i and j.
+func (h *HostHistory) Diff(i, j int) *HostDiff {
+	return DiffSnapshots(h.Snapshots[i], h.Snapshots[j])
+}
+
+// Empty reports whether there are no changes.
+func (d *HostDiff) Empty() bool {
+	return len(d.Opened) == 0 && len(d.Closed) == 0 && len(d.Changed) == 0 &&
+		len(d.CertificateRotations) == 0 && len(d.NewVulns) == 0
+}
+
+// DiffSnapshots compares two snapshots.
+func DiffSnapshots(from, to *HostSnapshot) *HostDiff {
+	diff := &HostDiff{
+		From:                 from.Timestamp,
+		To:                   to.Timestamp,
+		Opened:               make([]*HostData, 0),
+		Closed:               make([]*HostData, 0),
+		Changed:              make([]*ServiceChange, 0),
+		CertificateRotations: make([]*ServiceChange, 0),
+		NewVulns:             make([]*VulnChange, 0),
+	}
+
+	knownVulns := make(map[string]bool)
+	for _, data := range from.Services {
+		for cve := range data.Vulns {
+			knownVulns[cve] = true
+		}
+	}
+
+	for _, key := range sortedServiceKeys(to.Services) {
+		data := to.Services[key]
+
+		for _, cve := range sortedVulnIDs(data.Vulns) {
+			if !knownVulns[cve] {
+				diff.NewVulns = append(diff.NewVulns, &VulnChange{CVE: cve, Service: key, HostVulnerability: data.Vulns[cve]})
+			}
+		}
+
+		previous, ok := from.Services[key]
+		if !ok {
+			diff.Opened = append(diff.Opened, data)
+			continue
+		}
+
+		diff.Changed = appendServiceChange(diff.Changed, key, "product", previous.Product, data.Product)
+		diff.Changed = appendServiceChange(diff.Changed, key, "version", previous.Version.String(), data.Version.String())
+		diff.CertificateRotations = appendServiceChange(diff.CertificateRotations, key, "fingerprint",
+			certificateFingerprint(previous), certificateFingerprint(data))
+	}
+
+	for _, key := range sortedServiceKeys(from.Services) {
+		if _, ok := to.Services[key]; !ok {
+			diff.Closed = append(diff.Closed, from.Services[key])
+		}
+	}
+
+	return diff
+}
+
+func appendServiceChange(changes []*ServiceChange, key ServiceKey, field, from, to string) []*ServiceChange {
+	if from == to {
+		return changes
+	}
+
+	return append(changes, &ServiceChange{Service: key, Field: field, From: from, To: to})
+}
+
+// certificateFingerprint returns SHA256 (or SHA1 if missing) fingerprint of the service certificate.
+func certificateFingerprint(data *HostData) string {
+	if data.SSL == nil || data.SSL.Certificate == nil {
+		return ""
+	}
+
+	if fingerprint, ok := data.SSL.Certificate.Fingerprint["sha256"]; ok {
+		return fingerprint
+	}
+
+	return data.SSL.Certificate.Fingerprint["sha1"]
+}
+
+func sortedServiceKeys(services map[ServiceKey]*HostData) []ServiceKey {
+	keys := make([]ServiceKey, 0, len(services))
+	for key := range services {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Port != keys[j].Port {
+			return keys[i].Port < keys[j].Port
+		}
+
+		return keys[i].Transport < keys[j].Transport
+	})
+
+	return keys
+}
+
+func sortedVulnIDs(vulns map[string]*HostVulnerability) []string {
+	ids := make([]string, 0, len(vulns))
+	for id := range vulns {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
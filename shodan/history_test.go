@@ -0,0 +1,91 @@
+package shodan
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetHostHistory(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(hostPath+"/93.184.216.34", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "true", r.URL.Query().Get("history"))
+		w.Write(getStub(t, "host/history"))
+	})
+
+	history, err := client.GetHostHistory(context.TODO(), "93.184.216.34", nil)
+	assert.Nil(t, err)
+	assert.Len(t, history.Snapshots, 6)
+	assert.Equal(t, time.Date(2021, 1, 1, 8, 0, 0, 0, time.UTC), history.Snapshots[0].Timestamp)
+
+	history, err = client.GetHostHistory(context.TODO(), "93.184.216.34", &HostHistoryOptions{Resolution: 24 * time.Hour})
+	assert.Nil(t, err)
+	assert.Equal(t, "93.184.216.34", history.IP.String())
+	assert.Len(t, history.Snapshots, 2)
+	assert.Len(t, history.Snapshots[0].Services, 3)
+	assert.Len(t, history.Snapshots[1].Services, 3)
+}
+
+func TestHostHistory_Diff(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(hostPath+"/93.184.216.34", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "host/history"))
+	})
+
+	history, err := client.GetHostHistory(context.TODO(), "93.184.216.34", &HostHistoryOptions{Resolution: 24 * time.Hour})
+	assert.Nil(t, err)
+
+	diff := history.Diff(0, 1)
+	http80 := ServiceKey{Port: 80, Transport: "tcp"}
+
+	assert.False(t, diff.Empty())
+	assert.Len(t, diff.Opened, 1)
+	assert.Equal(t, 8080, diff.Opened[0].Port)
+	assert.Len(t, diff.Closed, 1)
+	assert.Equal(t, 22, diff.Closed[0].Port)
+	assert.Equal(t, []*ServiceChange{{Service: http80, Field: "version", From: "2.4.29", To: "2.4.46"}}, diff.Changed)
+	assert.Equal(t, []*ServiceChange{
+		{Service: ServiceKey{Port: 443, Transport: "tcp"}, Field: "fingerprint", From: "1111", To: "2222"},
+	}, diff.CertificateRotations)
+	assert.Len(t, diff.NewVulns, 1)
+	assert.Equal(t, "CVE-2021-26691", diff.NewVulns[0].CVE)
+	assert.Equal(t, http80, diff.NewVulns[0].Service)
+	assert.Equal(t, 7.5, diff.NewVulns[0].CVSS)
+
+	assert.True(t, history.Diff(1, 1).Empty())
+	assert.Equal(t, "443/tcp", diff.CertificateRotations[0].Service.String())
+}
+
+func TestHostHistory_DiffExactTimestamps(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(hostPath+"/93.184.216.34", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "host/history"))
+	})
+
+	history, err := client.GetHostHistory(context.TODO(), "93.184.216.34", nil)
+	assert.Nil(t, err)
+	assert.Len(t, history.Snapshots[1].Services, 2)
+	assert.Len(t, history.Snapshots[5].Services, 4)
+
+	diff := history.Diff(1, 2)
+	assert.Len(t, diff.Opened, 1)
+	assert.Equal(t, 443, diff.Opened[0].Port)
+	assert.Empty(t, diff.Closed)
+
+	diff = history.Diff(2, 3)
+	assert.Empty(t, diff.Opened)
+	assert.Empty(t, diff.Closed)
+	assert.Equal(t, []*ServiceChange{
+		{Service: ServiceKey{Port: 80, Transport: "tcp"}, Field: "version", From: "2.4.29", To: "2.4.46"},
+	}, diff.Changed)
+}
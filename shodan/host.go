@@ -2,8 +2,13 @@ package shodan
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
 	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
@@ -13,8 +18,21 @@ const (
 	hostSearchFacetsPath  = "/shodan/host/search/facets"
 	hostSearchFiltersPath = "/shodan/host/search/filters"
 	hostSearchTokensPath  = "/shodan/host/search/tokens" //nolint:gosec
+
+	timestampLayout = "2006-01-02T15:04:05.999999"
 )
 
+// ServiceKey identifies a service of the host.
+type ServiceKey struct {
+	Port      int
+	Transport string
+}
+
+// String returns the key in port/transport form, i.e. "443/tcp".
+func (k ServiceKey) String() string {
+	return strconv.Itoa(k.Port) + "/" + k.Transport
+}
+
 // HostServicesOptions is options for querying services.
 type HostServicesOptions struct {
 	History bool `url:"history,omitempty"`
@@ -130,6 +148,197 @@ type HostData struct {
 	Location     *HostLocation          `json:"location"`
 	ShodanData   map[string]interface{} `json:"_shodan"`
 	Opts         map[string]interface{} `json:"opts"`
+
+	HTTP    *HostHTTP    `json:"http,omitempty"`
+	SSH     *HostSSH     `json:"ssh,omitempty"`
+	FTP     *HostFTP     `json:"ftp,omitempty"`
+	SMB     *HostSMB     `json:"smb,omitempty"`
+	RDP     *HostRDP     `json:"rdp_encryption,omitempty"`
+	MongoDB *HostMongoDB `json:"mongodb,omitempty"`
+	Elastic *HostElastic `json:"elastic,omitempty"`
+	Redis   *HostRedis   `json:"redis,omitempty"`
+	VNC     *HostVNC     `json:"vnc,omitempty"`
+	SNMP    *HostSNMP    `json:"snmp,omitempty"`
+	NTP     *HostNTP     `json:"ntp,omitempty"`
+	MQTT    *HostMQTT    `json:"mqtt,omitempty"`
+	Modbus  *HostModbus  `json:"modbus,omitempty"`
+	S7      *HostS7      `json:"s7,omitempty"`
+
+	Vulns map[string]*HostVulnerability `json:"vulns,omitempty"`
+
+	// Extra holds the raw values of the keys that aren't modeled by HostData and of the modules
+	// that couldn't be decoded, so they are kept when the banner is encoded back.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// hostDataFields maps json keys modeled by HostData to its fields.
+var hostDataFields = jsonFields(reflect.TypeOf(HostData{}))
+
+// UnmarshalJSON implements Unmarshaler interface for 2 reasons:
+//
+// 1. collect the keys HostData doesn't model into Extra.
+// 2. keep the banner even if an optional part (i.e. a module) has unexpected layout.
+func (h *HostData) UnmarshalJSON(data []byte) error {
+	type Alias HostData
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	extra := make(map[string]json.RawMessage)
+
+	for key, value := range fields {
+		if _, ok := hostDataFields[key]; !ok {
+			extra[key] = value
+		}
+	}
+
+	if err := json.Unmarshal(data, (*Alias)(h)); err != nil {
+		broken, ok := brokenHostDataFields(fields)
+		if !ok {
+			return err
+		}
+
+		for _, key := range broken {
+			extra[key] = fields[key]
+			delete(fields, key)
+		}
+
+		if data, err = json.Marshal(fields); err != nil {
+			return err
+		}
+
+		*h = HostData{}
+		if err := json.Unmarshal(data, (*Alias)(h)); err != nil {
+			return err
+		}
+	}
+
+	h.Extra = nil
+	if len(extra) > 0 {
+		h.Extra = extra
+	}
+
+	return nil
+}
+
+// MarshalJSON implements Marshaler interface to write the keys from Extra back. The raw value of a module
+// that couldn't be decoded is written in place of the empty field unless the field was set since.
+func (h HostData) MarshalJSON() ([]byte, error) {
+	type Alias HostData
+
+	data, err := json.Marshal(Alias(h))
+	if err != nil || len(h.Extra) == 0 {
+		return data, err
+	}
+
+	var encoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+
+	value := reflect.ValueOf(h)
+
+	for key, raw := range h.Extra {
+		if field, ok := hostDataFields[key]; ok && !value.Field(field.index).IsZero() {
+			continue
+		}
+
+		if len(raw) == 0 {
+			raw = json.RawMessage("null")
+		}
+
+		encoded[key] = raw
+	}
+
+	return json.Marshal(encoded)
+}
+
+// Time parses the banner timestamp which is in UTC.
+func (h *HostData) Time() (time.Time, error) {
+	return time.Parse(timestampLayout, h.Timestamp)
+}
+
+// Service returns the key of the service the banner belongs to.
+func (h *HostData) Service() ServiceKey {
+	return ServiceKey{Port: h.Port, Transport: h.Transport}
+}
+
+// Tags returns the tags assigned to the banner by Shodan, i.e. "cloud" or "self-signed".
+func (h *HostData) Tags() []string {
+	var tags []string
+
+	if raw, ok := h.Extra["tags"]; ok {
+		if err := json.Unmarshal(raw, &tags); err != nil {
+			return nil
+		}
+	}
+
+	return tags
+}
+
+// SSLVersions returns the SSL/TLS versions supported by the service. Shodan prefixes the unsupported ones
+// with "-", they are skipped.
+func (h *HostData) SSLVersions() []string {
+	if h.SSL == nil {
+		return nil
+	}
+
+	versions := make([]string, 0, len(h.SSL.Versions))
+
+	for _, version := range h.SSL.Versions {
+		if !strings.HasPrefix(version, "-") {
+			versions = append(versions, version)
+		}
+	}
+
+	return versions
+}
+
+// CertificateSubjectCN returns the common name of the certificate subject or empty string.
+func (h *HostData) CertificateSubjectCN() string {
+	if h.SSL == nil || h.SSL.Certificate == nil || h.SSL.Certificate.Subject == nil {
+		return ""
+	}
+
+	return h.SSL.Certificate.Subject.CommonName
+}
+
+// CertificateIssuerCN returns the common name of the certificate issuer or empty string.
+func (h *HostData) CertificateIssuerCN() string {
+	if h.SSL == nil || h.SSL.Certificate == nil || h.SSL.Certificate.Issuer == nil {
+		return ""
+	}
+
+	return h.SSL.Certificate.Issuer.CommonName
+}
+
+// brokenHostDataFields returns the optional fields that can't be decoded. The second value is false
+// if a required field is broken.
+func brokenHostDataFields(fields map[string]json.RawMessage) ([]string, bool) {
+	t := reflect.TypeOf(HostData{})
+	broken := make([]string, 0)
+
+	for key, raw := range fields {
+		field, ok := hostDataFields[key]
+		if !ok {
+			continue
+		}
+
+		fieldType := t.Field(field.index).Type
+		if err := json.Unmarshal(raw, reflect.New(fieldType).Interface()); err == nil {
+			continue
+		}
+
+		if fieldType.Kind() != reflect.Ptr && fieldType.Kind() != reflect.Map {
+			return nil, false
+		}
+
+		broken = append(broken, key)
+	}
+
+	return broken, len(broken) > 0
 }
 
 // Host is the all information about the host.
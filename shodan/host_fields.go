@@ -0,0 +1,104 @@
+package shodan
+
+import (
+	"sort"
+	"strconv"
+)
+
+type hostDataField func(h *HostData) []string
+
+// hostDataFieldValues are the extractors of the HostData field values by the search filter and facet name.
+var hostDataFieldValues = map[string]hostDataField{
+	"asn":       singleField(func(h *HostData) string { return h.ASN }),
+	"city":      singleField(func(h *HostData) string { return h.location().City }),
+	"country":   singleField(func(h *HostData) string { return h.location().CountryCode }),
+	"domain":    func(h *HostData) []string { return h.Domains },
+	"hostname":  func(h *HostData) []string { return h.Hostnames },
+	"isp":       singleField(func(h *HostData) string { return h.ISP }),
+	"org":       singleField(func(h *HostData) string { return h.Organization }),
+	"os":        singleField(func(h *HostData) string { return h.OS }),
+	"port":      singleField(func(h *HostData) string { return strconv.Itoa(h.Port) }),
+	"product":   singleField(func(h *HostData) string { return h.Product }),
+	"version":   singleField(func(h *HostData) string { return h.Version.String() }),
+	"transport": singleField(func(h *HostData) string { return h.Transport }),
+	"vuln":      func(h *HostData) []string { return sortedVulnIDs(h.Vulns) },
+	"tag":       (*HostData).Tags,
+	"http.title": singleField(func(h *HostData) string {
+		if h.HTTP == nil {
+			return h.Title
+		}
+
+		return h.HTTP.Title
+	}),
+	"http.status": singleField(func(h *HostData) string {
+		if h.HTTP == nil || h.HTTP.Status == 0 {
+			return ""
+		}
+
+		return strconv.Itoa(h.HTTP.Status)
+	}),
+	"http.html": singleField(func(h *HostData) string {
+		if h.HTTP == nil {
+			return h.HTML
+		}
+
+		return h.HTTP.HTML
+	}),
+	"ssl.version": (*HostData).SSLVersions,
+	"ssl.cert.expired": singleField(func(h *HostData) string {
+		if h.SSL == nil || h.SSL.Certificate == nil {
+			return ""
+		}
+
+		return strconv.FormatBool(h.SSL.Certificate.IsExpired)
+	}),
+	"ssl.cert.issuer.cn":  singleField((*HostData).CertificateIssuerCN),
+	"ssl.cert.subject.cn": singleField((*HostData).CertificateSubjectCN),
+}
+
+// FieldValues returns the values of the field named like the search filter or facet, i.e. "org"
+// or "http.title". Empty values are omitted. The second value is false if the field isn't supported,
+// see HostDataFieldNames.
+func (h *HostData) FieldValues(name string) ([]string, bool) {
+	field, ok := hostDataFieldValues[name]
+	if !ok {
+		return nil, false
+	}
+
+	values := make([]string, 0)
+
+	for _, value := range field(h) {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values, true
+}
+
+// HostDataFieldNames returns the sorted names of the fields supported by HostData.FieldValues.
+func HostDataFieldNames() []string {
+	names := make([]string, 0, len(hostDataFieldValues))
+
+	for name := range hostDataFieldValues {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func singleField(field func(h *HostData) string) hostDataField {
+	return func(h *HostData) []string {
+		return []string{field(h)}
+	}
+}
+
+func (h *HostData) location() *HostLocation {
+	if h.Location == nil {
+		return &HostLocation{}
+	}
+
+	return h.Location
+}
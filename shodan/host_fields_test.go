@@ -0,0 +1,40 @@
+package shodan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostData_FieldValues(t *testing.T) {
+	var banner HostData
+
+	payload := []byte(`{"port": 443, "asn": "AS15169", "title": "Welcome", "hostnames": ["a.example.com"],
+		"vulns": {"CVE-2021-23017": {}, "CVE-2019-20372": {}}, "ssl": {"cert": {"expired": true}}}`)
+
+	assert.Nil(t, json.Unmarshal(payload, &banner))
+
+	values, ok := banner.FieldValues("port")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"443"}, values)
+
+	values, _ = banner.FieldValues("http.title")
+	assert.Equal(t, []string{"Welcome"}, values)
+
+	values, _ = banner.FieldValues("vuln")
+	assert.Equal(t, []string{"CVE-2019-20372", "CVE-2021-23017"}, values)
+
+	values, _ = banner.FieldValues("ssl.cert.expired")
+	assert.Equal(t, []string{"true"}, values)
+
+	values, ok = banner.FieldValues("country")
+	assert.True(t, ok)
+	assert.Empty(t, values)
+
+	_, ok = banner.FieldValues("screenshot.label")
+	assert.False(t, ok)
+
+	assert.Contains(t, HostDataFieldNames(), "http.title")
+	assert.Equal(t, HostDataFieldNames(), LocalFacets())
+}
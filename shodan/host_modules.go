@@ -0,0 +1,235 @@
+package shodan
+
+// HostHTTPFavicon is the favicon of the website.
+type HostHTTPFavicon struct {
+	Hash     int    `json:"hash"`
+	Data     string `json:"data"`
+	Location string `json:"location"`
+}
+
+// HostHTTPRedirect is a single redirect followed by the crawler.
+type HostHTTPRedirect struct {
+	Host     string `json:"host"`
+	Data     string `json:"data"`
+	Location string `json:"location"`
+}
+
+// HostHTTPComponent is a web technology detected on the website.
+type HostHTTPComponent struct {
+	Categories []string `json:"categories"`
+}
+
+// HostHTTP holds the information about web servers.
+type HostHTTP struct {
+	Status          int                           `json:"status"`
+	Title           string                        `json:"title"`
+	Host            string                        `json:"host"`
+	Location        string                        `json:"location"`
+	Server          string                        `json:"server"`
+	HTML            string                        `json:"html"`
+	HTMLHash        int                           `json:"html_hash"`
+	HeadersHash     int                           `json:"headers_hash"`
+	Robots          string                        `json:"robots"`
+	RobotsHash      int                           `json:"robots_hash"`
+	SecurityTXT     string                        `json:"securitytxt"`
+	SecurityTXTHash int                           `json:"securitytxt_hash"`
+	Sitemap         string                        `json:"sitemap"`
+	SitemapHash     int                           `json:"sitemap_hash"`
+	WAF             string                        `json:"waf"`
+	Favicon         *HostHTTPFavicon              `json:"favicon"`
+	Redirects       []*HostHTTPRedirect           `json:"redirects"`
+	Components      map[string]*HostHTTPComponent `json:"components"`
+}
+
+// HostSSHKex is the key exchange initialization of SSH server.
+type HostSSHKex struct {
+	KexAlgorithms           []string `json:"kex_algorithms"`
+	ServerHostKeyAlgorithms []string `json:"server_host_key_algorithms"`
+	EncryptionAlgorithms    []string `json:"encryption_algorithms"`
+	MACAlgorithms           []string `json:"mac_algorithms"`
+	CompressionAlgorithms   []string `json:"compression_algorithms"`
+	Languages               []string `json:"languages"`
+	KexFollows              bool     `json:"kex_follows"`
+	Unused                  int      `json:"unused"`
+}
+
+// HostSSH holds the information about SSH servers.
+type HostSSH struct {
+	Type        string      `json:"type"`
+	Cipher      string      `json:"cipher"`
+	Fingerprint string      `json:"fingerprint"`
+	Key         string      `json:"key"`
+	MAC         string      `json:"mac"`
+	HASSH       string      `json:"hassh"`
+	Kex         *HostSSHKex `json:"kex"`
+}
+
+// HostFTPFeature is a feature announced by FTP server.
+type HostFTPFeature struct {
+	Parameters []string `json:"parameters"`
+}
+
+// HostFTP holds the information about FTP servers.
+type HostFTP struct {
+	Anonymous    bool                       `json:"anonymous"`
+	Features     map[string]*HostFTPFeature `json:"features"`
+	FeaturesHash int                        `json:"features_hash"`
+}
+
+// HostSMBFile is a file found on SMB share.
+type HostSMBFile struct {
+	Name      string `json:"name"`
+	Size      int64  `json:"size"`
+	Directory bool   `json:"directory"`
+	ReadOnly  bool   `json:"read-only"`
+}
+
+// HostSMBShare is a share of SMB server.
+type HostSMBShare struct {
+	Name      string         `json:"name"`
+	Comments  string         `json:"comments"`
+	Type      string         `json:"type"`
+	Temporary bool           `json:"temporary"`
+	Special   bool           `json:"special"`
+	Files     []*HostSMBFile `json:"files"`
+}
+
+// HostSMB holds the information about SMB servers.
+type HostSMB struct {
+	SMBVersion   int             `json:"smb_version"`
+	Anonymous    bool            `json:"anonymous"`
+	Capabilities []string        `json:"capabilities"`
+	OS           string          `json:"os"`
+	Software     string          `json:"software"`
+	Raw          []string        `json:"raw"`
+	Shares       []*HostSMBShare `json:"shares"`
+}
+
+// HostRDP holds encryption capabilities of Remote Desktop Protocol servers.
+type HostRDP struct {
+	Levels    []string `json:"levels"`
+	Methods   []string `json:"methods"`
+	Protocols []string `json:"protocols"`
+}
+
+// HostMongoDBBuildInfo is the output of MongoDB buildInfo command.
+type HostMongoDBBuildInfo struct {
+	Version          string `json:"version"`
+	GitVersion       string `json:"gitVersion"`
+	Allocator        string `json:"allocator"`
+	JavascriptEngine string `json:"javascriptEngine"`
+	SysInfo          string `json:"sysInfo"`
+	Bits             int    `json:"bits"`
+	Debug            bool   `json:"debug"`
+}
+
+// HostMongoDBDatabase is a database of MongoDB server.
+type HostMongoDBDatabase struct {
+	Name       string  `json:"name"`
+	SizeOnDisk float64 `json:"sizeOnDisk"`
+	Empty      bool    `json:"empty"`
+}
+
+// HostMongoDBDatabases is the output of MongoDB listDatabases command.
+type HostMongoDBDatabases struct {
+	TotalSize float64                `json:"totalSize"`
+	Databases []*HostMongoDBDatabase `json:"databases"`
+}
+
+// HostMongoDB holds the information about MongoDB servers.
+type HostMongoDB struct {
+	Authentication bool                   `json:"authentication"`
+	BuildInfo      *HostMongoDBBuildInfo  `json:"buildInfo"`
+	ListDatabases  *HostMongoDBDatabases  `json:"listDatabases"`
+	ServerStatus   map[string]interface{} `json:"serverStatus"`
+}
+
+// HostElasticCluster is the cluster statistics of Elasticsearch.
+type HostElasticCluster struct {
+	ClusterName string `json:"cluster_name"`
+	ClusterUUID string `json:"cluster_uuid"`
+	Status      string `json:"status"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+// HostElastic holds the information about Elasticsearch servers.
+type HostElastic struct {
+	Cluster *HostElasticCluster    `json:"cluster"`
+	Indices map[string]interface{} `json:"indices"`
+	Nodes   map[string]interface{} `json:"nodes"`
+}
+
+// HostRedisKeys is a sample of keys stored in Redis.
+type HostRedisKeys struct {
+	Data []string `json:"data"`
+	More bool     `json:"more"`
+}
+
+// HostRedis holds the information about Redis servers. Sections are the output of INFO command.
+type HostRedis struct {
+	Server      map[string]interface{} `json:"server"`
+	Clients     map[string]interface{} `json:"clients"`
+	Memory      map[string]interface{} `json:"memory"`
+	Persistence map[string]interface{} `json:"persistence"`
+	Stats       map[string]interface{} `json:"stats"`
+	Replication map[string]interface{} `json:"replication"`
+	CPU         map[string]interface{} `json:"cpu"`
+	Keyspace    map[string]interface{} `json:"keyspace"`
+	Keys        *HostRedisKeys         `json:"keys"`
+}
+
+// HostVNC holds the information about VNC servers.
+type HostVNC struct {
+	ProtocolVersion string            `json:"protocol_version"`
+	SecurityTypes   map[string]string `json:"security_types"`
+}
+
+// HostSNMP holds the information about SNMP agents.
+type HostSNMP struct {
+	Versions    []string `json:"versions"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Contact     string   `json:"contact"`
+	Location    string   `json:"location"`
+	ObjectID    string   `json:"objectid"`
+	Uptime      string   `json:"uptime"`
+	Services    string   `json:"services"`
+}
+
+// HostNTP holds the information about NTP servers.
+type HostNTP struct {
+	Version        int         `json:"version"`
+	Stratum        int         `json:"stratum"`
+	Leap           int         `json:"leap"`
+	Precision      int         `json:"precision"`
+	Poll           int         `json:"poll"`
+	RootDelay      float64     `json:"rootdelay"`
+	RootDispersion float64     `json:"rootdisp"`
+	ClockOffset    float64     `json:"clock_offset"`
+	Delay          float64     `json:"delay"`
+	Monlist        interface{} `json:"monlist"`
+}
+
+// HostMQTTMessage is a message received from MQTT broker.
+type HostMQTTMessage struct {
+	Topic   string `json:"topic"`
+	Payload string `json:"payload"`
+}
+
+// HostMQTT holds the information about MQTT brokers.
+type HostMQTT struct {
+	Code     int                `json:"code"`
+	Messages []*HostMQTTMessage `json:"messages"`
+}
+
+// HostModbus holds the information about Modbus devices.
+type HostModbus struct {
+	UnitID               int                    `json:"unit_id"`
+	DeviceIdentification string                 `json:"device_identification"`
+	Registers            map[string]interface{} `json:"registers"`
+}
+
+// HostS7 holds the information about Siemens S7 PLCs.
+type HostS7 struct {
+	Identities map[string]interface{} `json:"identities"`
+}
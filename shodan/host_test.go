@@ -102,3 +102,123 @@ func TestClient_GetFilters(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, expectedFilters, actualFilters)
 }
+
+func TestHostData_UnmarshalJSONModules(t *testing.T) {
+	var banner HostData
+
+	assert.Nil(t, json.Unmarshal(getStub(t, "host/modules"), &banner))
+
+	assert.Equal(t, 200, banner.HTTP.Status)
+	assert.Equal(t, "Example Domain", banner.HTTP.Title)
+	assert.Equal(t, 708578229, banner.HTTP.Favicon.Hash)
+	assert.Equal(t, []string{"Web servers"}, banner.HTTP.Components["Nginx"].Categories)
+	assert.Equal(t, "b12d2871a1189eff20364cf5333619ee", banner.SSH.HASSH)
+	assert.Equal(t, []string{"curve25519-sha256"}, banner.SSH.Kex.KexAlgorithms)
+	assert.False(t, banner.MongoDB.Authentication)
+	assert.Equal(t, "3.6.8", banner.MongoDB.BuildInfo.Version)
+	assert.Equal(t, "admin", banner.MongoDB.ListDatabases.Databases[0].Name)
+	assert.Equal(t, "VNC Authentication", banner.VNC.SecurityTypes["2"])
+	assert.Nil(t, banner.FTP)
+
+	assert.Len(t, banner.Extra, 3)
+	assert.JSONEq(t, `{"provider": "Azure", "region": "westeurope"}`, string(banner.Extra["cloud"]))
+	assert.JSONEq(t, `["cdn"]`, string(banner.Extra["tags"]))
+}
+
+func TestHostData_UnmarshalJSONBrokenModule(t *testing.T) {
+	var banner HostData
+
+	payload := []byte(`{"ip_str": "127.0.0.1", "port": 21, "ftp": {"anonymous": "maybe"}, "vnc": {}}`)
+
+	assert.Nil(t, json.Unmarshal(payload, &banner))
+	assert.Equal(t, 21, banner.Port)
+	assert.Nil(t, banner.FTP)
+	assert.NotNil(t, banner.VNC)
+	assert.JSONEq(t, `{"anonymous": "maybe"}`, string(banner.Extra["ftp"]))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"port": "twenty one"}`), &banner))
+}
+
+func TestHostData_MarshalJSONRoundTrip(t *testing.T) {
+	payload := getStub(t, "host/modules")
+
+	var banner HostData
+	assert.Nil(t, json.Unmarshal(payload, &banner))
+
+	encoded, err := json.Marshal(&banner)
+	assert.Nil(t, err)
+
+	var original, restored map[string]interface{}
+	assert.Nil(t, json.Unmarshal(payload, &original))
+	assert.Nil(t, json.Unmarshal(encoded, &restored))
+
+	for key, value := range original {
+		if _, ok := restored[key].(map[string]interface{}); ok {
+			// modules are compared below as typed values add zero fields
+			continue
+		}
+
+		assert.Equal(t, value, restored[key], key)
+	}
+
+	var decoded HostData
+	assert.Nil(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, banner.HTTP, decoded.HTTP)
+	assert.Equal(t, banner.MongoDB, decoded.MongoDB)
+
+	reencoded, err := json.Marshal(&decoded)
+	assert.Nil(t, err)
+	assert.JSONEq(t, string(encoded), string(reencoded))
+}
+
+func TestHostData_MarshalJSONBrokenField(t *testing.T) {
+	var banner HostData
+
+	payload := []byte(`{"ip_str": "127.0.0.1", "port": 21, "location": "bogus", "ftp": {"anonymous": "maybe"}}`)
+	assert.Nil(t, json.Unmarshal(payload, &banner))
+	assert.Nil(t, banner.Location)
+
+	encoded, err := json.Marshal(&banner)
+	assert.Nil(t, err)
+
+	var restored map[string]interface{}
+	assert.Nil(t, json.Unmarshal(encoded, &restored))
+	assert.Equal(t, "bogus", restored["location"])
+	assert.Equal(t, map[string]interface{}{"anonymous": "maybe"}, restored["ftp"])
+
+	banner.Location = &HostLocation{City: "Paris"}
+	encoded, err = json.Marshal(&banner)
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(encoded, &restored))
+	assert.Equal(t, "Paris", restored["location"].(map[string]interface{})["city"])
+}
+
+func TestHostData_MarshalJSONValue(t *testing.T) {
+	var banner HostData
+	assert.Nil(t, json.Unmarshal([]byte(`{"port": 80, "tags": ["cdn"]}`), &banner))
+
+	for _, value := range []interface{}{banner, []HostData{banner}, struct{ Banner HostData }{banner}} {
+		encoded, err := json.Marshal(value)
+		assert.Nil(t, err)
+		assert.Contains(t, string(encoded), `"tags":["cdn"]`)
+	}
+}
+
+func TestHostData_Helpers(t *testing.T) {
+	var banner HostData
+
+	payload := []byte(`{"port": 443, "tags": ["cdn", "cloud"], "ssl": {"versions": ["TLSv1.2", "-SSLv3"],
+		"cert": {"subject": {"CN": "example.com"}, "issuer": {"CN": "Example CA"}}}}`)
+
+	assert.Nil(t, json.Unmarshal(payload, &banner))
+	assert.Equal(t, []string{"cdn", "cloud"}, banner.Tags())
+	assert.Equal(t, []string{"TLSv1.2"}, banner.SSLVersions())
+	assert.Equal(t, "example.com", banner.CertificateSubjectCN())
+	assert.Equal(t, "Example CA", banner.CertificateIssuerCN())
+
+	empty := &HostData{}
+	assert.Nil(t, empty.Tags())
+	assert.Nil(t, empty.SSLVersions())
+	assert.Equal(t, "", empty.CertificateSubjectCN())
+	assert.Equal(t, "", empty.CertificateIssuerCN())
+}
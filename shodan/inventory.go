@@ -0,0 +1,419 @@
+package shodan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// InventoryOptions describes the assets to take the snapshot of.
+type InventoryOptions struct {
+	// Networks are CIDRs or single IPs searched with net: filter.
+	Networks []string
+
+	// Queries are arbitrary search queries, i.e. org:"Example Inc".
+	Queries []string
+
+	// MaxPages limits the number of pages walked per network or query (0 means no limit).
+	MaxPages int
+}
+
+// InventoryRecord is the normalized state of a service.
+type InventoryRecord struct {
+	IP                     string   `json:"ip"`
+	Port                   int      `json:"port"`
+	Transport              string   `json:"transport"`
+	Product                string   `json:"product,omitempty"`
+	Version                string   `json:"version,omitempty"`
+	CertificateFingerprint string   `json:"cert_fingerprint,omitempty"`
+	Vulns                  []string `json:"vulns,omitempty"`
+	Timestamp              string   `json:"timestamp,omitempty"`
+}
+
+// Service returns the key of the service.
+func (r *InventoryRecord) Service() ServiceKey {
+	return ServiceKey{Port: r.Port, Transport: r.Transport}
+}
+
+func (r *InventoryRecord) key() string {
+	return r.IP + " " + r.Service().String()
+}
+
+// InventorySnapshot is the set of services observed at once sorted by IP, port and transport.
+type InventorySnapshot struct {
+	Taken   time.Time          `json:"taken"`
+	Sources []string           `json:"sources,omitempty"`
+	Records []*InventoryRecord `json:"records"`
+}
+
+// InventoryVersionChange is a product or version change of a service present in both snapshots.
+type InventoryVersionChange struct {
+	IP          string `json:"ip"`
+	Port        int    `json:"port"`
+	Transport   string `json:"transport"`
+	FromProduct string `json:"from_product,omitempty"`
+	FromVersion string `json:"from_version,omitempty"`
+	ToProduct   string `json:"to_product,omitempty"`
+	ToVersion   string `json:"to_version,omitempty"`
+}
+
+// InventoryCertificateChange is a certificate fingerprint change of a service present in both snapshots.
+type InventoryCertificateChange struct {
+	IP        string `json:"ip"`
+	Port      int    `json:"port"`
+	Transport string `json:"transport"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+}
+
+// InventoryCVE is a vulnerability the service wasn't affected by in the earlier snapshot.
+type InventoryCVE struct {
+	IP        string `json:"ip"`
+	Port      int    `json:"port"`
+	Transport string `json:"transport"`
+	CVE       string `json:"cve"`
+}
+
+// InventoryReport describes the changes between two inventory snapshots.
+type InventoryReport struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// NewExposures are services present only in the later snapshot.
+	NewExposures []*InventoryRecord `json:"new_exposures"`
+
+	// ClosedServices are services present only in the earlier snapshot.
+	ClosedServices []*InventoryRecord `json:"closed_services"`
+
+	// VersionChanges are product and version changes of services present in both snapshots.
+	VersionChanges []*InventoryVersionChange `json:"version_changes"`
+
+	// CertificateChanges are certificate rotations of services present in both snapshots.
+	CertificateChanges []*InventoryCertificateChange `json:"certificate_changes"`
+
+	// NewCVEs are vulnerabilities of services (including new exposures) not reported for them before.
+	NewCVEs []*InventoryCVE `json:"new_cves"`
+}
+
+// TakeInventorySnapshot searches the networks and queries and normalizes the found services. A service
+// found by several searches is recorded once.
+func (c *Client) TakeInventorySnapshot(ctx context.Context, options *InventoryOptions) (*InventorySnapshot, error) {
+	queries := make([]string, 0, len(options.Networks)+len(options.Queries))
+
+	for _, network := range options.Networks {
+		if _, _, err := net.ParseCIDR(network); err != nil && net.ParseIP(network) == nil {
+			return nil, fmt.Errorf("%w: %s is neither IP nor CIDR", ErrInvalidNetwork, network)
+		}
+
+		queries = append(queries, "net:"+network)
+	}
+
+	queries = append(queries, options.Queries...)
+	banners := make([]*HostData, 0)
+
+	for _, query := range queries {
+		it := c.NewSearchIterator(&HostQueryOptions{Query: query},
+			&SearchIteratorOptions{MaxPages: options.MaxPages})
+
+		for {
+			banner, err := it.Next(ctx)
+			if err == ErrIteratorDone {
+				break
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			banners = append(banners, banner)
+		}
+	}
+
+	snapshot := NewInventorySnapshot(banners)
+	snapshot.Sources = queries
+
+	return snapshot, nil
+}
+
+// NewInventorySnapshot normalizes the banners taken now. The latest banner of every service is recorded.
+func NewInventorySnapshot(banners []*HostData) *InventorySnapshot {
+	latest := make(map[string]*HostData)
+
+	for _, banner := range banners {
+		if banner.IP == nil {
+			continue
+		}
+
+		record := newInventoryRecord(banner)
+		if known, ok := latest[record.key()]; !ok || known.Timestamp < banner.Timestamp {
+			latest[record.key()] = banner
+		}
+	}
+
+	snapshot := &InventorySnapshot{Taken: time.Now().UTC(), Records: make([]*InventoryRecord, 0, len(latest))}
+	for _, banner := range latest {
+		snapshot.Records = append(snapshot.Records, newInventoryRecord(banner))
+	}
+
+	sortInventoryRecords(snapshot.Records)
+
+	return snapshot
+}
+
+// LoadInventorySnapshot reads the snapshot saved with Save.
+func LoadInventorySnapshot(path string) (*InventorySnapshot, error) {
+	data, err := ioutil.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := new(InventorySnapshot)
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Save writes the snapshot as JSON. The file is replaced atomically so a crash never leaves a partial snapshot.
+func (s *InventorySnapshot) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Diff compares the snapshot with the later one.
+func (s *InventorySnapshot) Diff(to *InventorySnapshot) *InventoryReport {
+	return DiffInventory(s, to)
+}
+
+// DiffInventory compares two snapshots.
+func DiffInventory(from, to *InventorySnapshot) *InventoryReport {
+	report := &InventoryReport{
+		From:               from.Taken,
+		To:                 to.Taken,
+		NewExposures:       make([]*InventoryRecord, 0),
+		ClosedServices:     make([]*InventoryRecord, 0),
+		VersionChanges:     make([]*InventoryVersionChange, 0),
+		CertificateChanges: make([]*InventoryCertificateChange, 0),
+		NewCVEs:            make([]*InventoryCVE, 0),
+	}
+
+	previous := make(map[string]*InventoryRecord, len(from.Records))
+	for _, record := range from.Records {
+		previous[record.key()] = record
+	}
+
+	current := make(map[string]bool, len(to.Records))
+
+	for _, record := range to.Records {
+		current[record.key()] = true
+
+		known, ok := previous[record.key()]
+		if !ok {
+			report.NewExposures = append(report.NewExposures, record)
+			known = &InventoryRecord{}
+		}
+
+		if ok && known.CertificateFingerprint != record.CertificateFingerprint {
+			report.CertificateChanges = append(report.CertificateChanges, &InventoryCertificateChange{
+				IP:        record.IP,
+				Port:      record.Port,
+				Transport: record.Transport,
+				From:      known.CertificateFingerprint,
+				To:        record.CertificateFingerprint,
+			})
+		}
+
+		if ok && (known.Product != record.Product || known.Version != record.Version) {
+			report.VersionChanges = append(report.VersionChanges, &InventoryVersionChange{
+				IP:          record.IP,
+				Port:        record.Port,
+				Transport:   record.Transport,
+				FromProduct: known.Product,
+				FromVersion: known.Version,
+				ToProduct:   record.Product,
+				ToVersion:   record.Version,
+			})
+		}
+
+		added, _ := diffStrings(known.Vulns, record.Vulns)
+		for _, cve := range added {
+			report.NewCVEs = append(report.NewCVEs, &InventoryCVE{
+				IP:        record.IP,
+				Port:      record.Port,
+				Transport: record.Transport,
+				CVE:       cve,
+			})
+		}
+	}
+
+	for _, record := range from.Records {
+		if !current[record.key()] {
+			report.ClosedServices = append(report.ClosedServices, record)
+		}
+	}
+
+	return report
+}
+
+// Empty reports whether there are no changes.
+func (r *InventoryReport) Empty() bool {
+	return len(r.NewExposures) == 0 && len(r.ClosedServices) == 0 && len(r.VersionChanges) == 0 &&
+		len(r.CertificateChanges) == 0 && len(r.NewCVEs) == 0
+}
+
+// JSON renders the report as indented JSON.
+func (r *InventoryReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Markdown renders the report as Markdown tables. Empty sections are omitted.
+func (r *InventoryReport) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("# Inventory changes\n\n")
+	fmt.Fprintf(&b, "From %s to %s.\n", r.From.Format(time.RFC3339), r.To.Format(time.RFC3339))
+
+	if r.Empty() {
+		b.WriteString("\nNo changes.\n")
+		return b.String()
+	}
+
+	recordRows := func(records []*InventoryRecord) [][]string {
+		rows := make([][]string, len(records))
+		for i, record := range records {
+			rows[i] = []string{record.IP, record.Service().String(), record.Product, record.Version}
+		}
+
+		return rows
+	}
+
+	writeMarkdownSection(&b, "New exposures", []string{"IP", "Service", "Product", "Version"},
+		recordRows(r.NewExposures))
+	writeMarkdownSection(&b, "Closed services", []string{"IP", "Service", "Product", "Version"},
+		recordRows(r.ClosedServices))
+
+	rows := make([][]string, len(r.VersionChanges))
+	for i, change := range r.VersionChanges {
+		service := ServiceKey{Port: change.Port, Transport: change.Transport}
+		rows[i] = []string{change.IP, service.String(),
+			joinNonEmpty(change.FromProduct, change.FromVersion), joinNonEmpty(change.ToProduct, change.ToVersion)}
+	}
+
+	writeMarkdownSection(&b, "Version changes", []string{"IP", "Service", "From", "To"}, rows)
+
+	rows = make([][]string, len(r.CertificateChanges))
+	for i, change := range r.CertificateChanges {
+		service := ServiceKey{Port: change.Port, Transport: change.Transport}
+		rows[i] = []string{change.IP, service.String(), change.From, change.To}
+	}
+
+	writeMarkdownSection(&b, "Certificate changes", []string{"IP", "Service", "From", "To"}, rows)
+
+	rows = make([][]string, len(r.NewCVEs))
+	for i, cve := range r.NewCVEs {
+		rows[i] = []string{cve.IP, ServiceKey{Port: cve.Port, Transport: cve.Transport}.String(), cve.CVE}
+	}
+
+	writeMarkdownSection(&b, "New CVEs", []string{"IP", "Service", "CVE"}, rows)
+
+	return b.String()
+}
+
+func writeMarkdownSection(b *strings.Builder, title string, header []string, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n## %s (%d)\n\n", title, len(rows))
+	writeMarkdownRow(b, header)
+
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+
+	writeMarkdownRow(b, separator)
+
+	for _, row := range rows {
+		writeMarkdownRow(b, row)
+	}
+}
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.ReplaceAll(cell, "|", `\|`)
+	}
+
+	b.WriteString("| " + strings.Join(escaped, " | ") + " |\n")
+}
+
+func joinNonEmpty(values ...string) string {
+	nonEmpty := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+
+	return strings.Join(nonEmpty, " ")
+}
+
+func newInventoryRecord(banner *HostData) *InventoryRecord {
+	key := banner.Service()
+
+	return &InventoryRecord{
+		IP:                     banner.IP.String(),
+		Port:                   key.Port,
+		Transport:              key.Transport,
+		Product:                banner.Product,
+		Version:                banner.Version.String(),
+		CertificateFingerprint: certificateFingerprint(banner),
+		Vulns:                  sortedVulnIDs(banner.Vulns),
+		Timestamp:              banner.Timestamp,
+	}
+}
+
+func sortInventoryRecords(records []*InventoryRecord) {
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.IP != b.IP {
+			return CompareIPs(a.IP, b.IP) < 0
+		}
+
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+
+		return a.Transport < b.Transport
+	})
+}
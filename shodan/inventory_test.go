@@ -0,0 +1,185 @@
+package shodan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func inventoryBanner(ip string, port int, product, version string, vulns ...string) *HostData {
+	banner := &HostData{
+		IP:        net.ParseIP(ip),
+		Port:      port,
+		Transport: "tcp",
+		Product:   product,
+		Version:   IntString(version),
+		Timestamp: "2021-01-01T00:00:00.000000",
+		Vulns:     make(map[string]*HostVulnerability),
+	}
+
+	for _, cve := range vulns {
+		banner.Vulns[cve] = &HostVulnerability{}
+	}
+
+	return banner
+}
+
+func TestClient_TakeInventorySnapshot(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	queries := make([]string, 0)
+
+	mux.HandleFunc(hostSearchPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		queries = append(queries, query)
+
+		found := &HostMatch{Matches: []*HostData{inventoryBanner("192.0.2.1", 443, "nginx", "1.18.0", "CVE-2021-23017")}}
+		if query == `org:"Example Inc"` {
+			found.Matches = append(found.Matches, inventoryBanner("198.51.100.7", 22, "OpenSSH", "8.2"))
+		}
+
+		found.Total = len(found.Matches)
+
+		b, _ := json.Marshal(found)
+		w.Write(b)
+	})
+
+	snapshot, err := client.TakeInventorySnapshot(context.TODO(), &InventoryOptions{
+		Networks: []string{"192.0.2.0/24"},
+		Queries:  []string{`org:"Example Inc"`},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"net:192.0.2.0/24", `org:"Example Inc"`}, queries)
+	assert.Equal(t, queries, snapshot.Sources)
+	assert.Equal(t, []*InventoryRecord{
+		{
+			IP:        "192.0.2.1",
+			Port:      443,
+			Transport: "tcp",
+			Product:   "nginx",
+			Version:   "1.18.0",
+			Vulns:     []string{"CVE-2021-23017"},
+			Timestamp: "2021-01-01T00:00:00.000000",
+		},
+		{
+			IP:        "198.51.100.7",
+			Port:      22,
+			Transport: "tcp",
+			Product:   "OpenSSH",
+			Version:   "8.2",
+			Vulns:     []string{},
+			Timestamp: "2021-01-01T00:00:00.000000",
+		},
+	}, snapshot.Records)
+
+	_, err = client.TakeInventorySnapshot(context.TODO(), &InventoryOptions{Networks: []string{"192.0.2.0/33"}})
+	assert.True(t, errors.Is(err, ErrInvalidNetwork))
+}
+
+func TestInventorySnapshot_Save(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-shodan")
+	assert.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "inventory.json")
+	snapshot := NewInventorySnapshot([]*HostData{inventoryBanner("192.0.2.1", 443, "nginx", "1.18.0", "CVE-2021-23017")})
+	snapshot.Sources = []string{"net:192.0.2.0/24"}
+
+	assert.Nil(t, snapshot.Save(path))
+	assert.Nil(t, snapshot.Save(path))
+
+	loaded, err := LoadInventorySnapshot(path)
+	assert.Nil(t, err)
+	assert.True(t, snapshot.Taken.Equal(loaded.Taken))
+	assert.Equal(t, snapshot.Sources, loaded.Sources)
+	assert.Equal(t, snapshot.Records, loaded.Records)
+
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+
+	_, err = LoadInventorySnapshot(filepath.Join(dir, "missing.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func withCertificate(banner *HostData, fingerprint string) *HostData {
+	banner.SSL = &HostSSL{Certificate: &HostCertificate{Fingerprint: map[string]string{"sha256": fingerprint}}}
+	return banner
+}
+
+func TestDiffInventory(t *testing.T) {
+	from := NewInventorySnapshot([]*HostData{
+		withCertificate(inventoryBanner("192.0.2.1", 443, "nginx", "1.18.0"), "1111"),
+		inventoryBanner("192.0.2.1", 80, "nginx", "1.18.0"),
+		inventoryBanner("192.0.2.10", 21, "vsftpd", "3.0.3"),
+	})
+
+	to := NewInventorySnapshot([]*HostData{
+		withCertificate(inventoryBanner("192.0.2.1", 443, "nginx", "1.20.1", "CVE-2021-23017"), "2222"),
+		inventoryBanner("192.0.2.1", 80, "nginx", "1.18.0"),
+		inventoryBanner("192.0.2.9", 3306, "MySQL", "5.7.33", "CVE-2021-2154"),
+	})
+
+	assert.True(t, DiffInventory(from, from).Empty())
+
+	report := from.Diff(to)
+	assert.False(t, report.Empty())
+	assert.Equal(t, []*InventoryRecord{to.Records[2]}, report.NewExposures)
+	assert.Equal(t, []*InventoryRecord{from.Records[2]}, report.ClosedServices)
+	assert.Equal(t, []*InventoryVersionChange{{
+		IP:          "192.0.2.1",
+		Port:        443,
+		Transport:   "tcp",
+		FromProduct: "nginx",
+		FromVersion: "1.18.0",
+		ToProduct:   "nginx",
+		ToVersion:   "1.20.1",
+	}}, report.VersionChanges)
+	assert.Equal(t, []*InventoryCertificateChange{
+		{IP: "192.0.2.1", Port: 443, Transport: "tcp", From: "1111", To: "2222"},
+	}, report.CertificateChanges)
+	assert.Equal(t, []*InventoryCVE{
+		{IP: "192.0.2.1", Port: 443, Transport: "tcp", CVE: "CVE-2021-23017"},
+		{IP: "192.0.2.9", Port: 3306, Transport: "tcp", CVE: "CVE-2021-2154"},
+	}, report.NewCVEs)
+
+	data, err := report.JSON()
+	assert.Nil(t, err)
+
+	decoded := new(InventoryReport)
+	assert.Nil(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, report.NewCVEs, decoded.NewCVEs)
+
+	markdown := report.Markdown()
+	assert.Contains(t, markdown, "## New exposures (1)\n\n"+
+		"| IP | Service | Product | Version |\n"+
+		"| --- | --- | --- | --- |\n"+
+		"| 192.0.2.9 | 3306/tcp | MySQL | 5.7.33 |\n")
+	assert.Contains(t, markdown, "## Closed services (1)")
+	assert.Contains(t, markdown, "| 192.0.2.1 | 443/tcp | nginx 1.18.0 | nginx 1.20.1 |\n")
+	assert.Contains(t, markdown, "| 192.0.2.1 | 443/tcp | CVE-2021-23017 |\n")
+	assert.Contains(t, markdown, "## Certificate changes (1)\n\n"+
+		"| IP | Service | From | To |\n"+
+		"| --- | --- | --- | --- |\n"+
+		"| 192.0.2.1 | 443/tcp | 1111 | 2222 |\n")
+	assert.Contains(t, DiffInventory(from, from).Markdown(), "No changes.")
+}
+
+func TestCompareIPs(t *testing.T) {
+	assert.Equal(t, -1, CompareIPs("192.0.2.9", "192.0.2.10"))
+	assert.Equal(t, 1, CompareIPs("192.0.2.10", "192.0.2.9"))
+	assert.Equal(t, 0, CompareIPs("192.0.2.1", "::ffff:192.0.2.1"))
+	assert.Equal(t, -1, CompareIPs("2001:db8::9", "2001:db8::a"))
+	assert.Equal(t, -1, CompareIPs("bogus", "other"))
+}
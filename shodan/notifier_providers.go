@@ -0,0 +1,184 @@
+package shodan
+
+import (
+	"context"
+	"fmt"
+)
+
+// Known notification providers, see GetNotifierProviders for the full list.
+const (
+	NotifierProviderEmail     = "email"
+	NotifierProviderSlack     = "slack"
+	NotifierProviderTelegram  = "telegram"
+	NotifierProviderWebhook   = "webhook"
+	NotifierProviderPagerDuty = "pagerduty"
+	NotifierProviderGitter    = "gitter"
+)
+
+// defaultNotifierID is the notifier every account has, it can't be deleted.
+const defaultNotifierID = "default"
+
+// NotifiersDiff is the list of changes that turn the current notifiers into the desired ones.
+type NotifiersDiff struct {
+	// Create are desired notifiers missing from the account.
+	Create []*Notifier
+
+	// Update are desired notifiers with different arguments, the IDs of the current notifiers are set.
+	Update []*Notifier
+
+	// Delete are current notifiers that are not desired. The default notifier is never deleted.
+	Delete []*Notifier
+}
+
+// NewEmailNotifier creates a notifier sending emails to the address.
+func NewEmailNotifier(description, to string) *Notifier {
+	return newNotifier(NotifierProviderEmail, description, "to", to)
+}
+
+// NewSlackNotifier creates a notifier posting to the Slack incoming webhook.
+func NewSlackNotifier(description, webhookURL string) *Notifier {
+	return newNotifier(NotifierProviderSlack, description, "webhook_url", webhookURL)
+}
+
+// NewTelegramNotifier creates a notifier sending messages to the Telegram chat with the bot token.
+func NewTelegramNotifier(description, chatID, token string) *Notifier {
+	return newNotifier(NotifierProviderTelegram, description, "chat_id", chatID, "token", token)
+}
+
+// NewWebhookNotifier creates a notifier posting banners to the URL, see AlertWebhookHandler.
+func NewWebhookNotifier(description, url string) *Notifier {
+	return newNotifier(NotifierProviderWebhook, description, "url", url)
+}
+
+// NewPagerDutyNotifier creates a notifier triggering PagerDuty incidents with the integration key.
+func NewPagerDutyNotifier(description, routingKey string) *Notifier {
+	return newNotifier(NotifierProviderPagerDuty, description, "routing_key", routingKey)
+}
+
+// NewGitterNotifier creates a notifier posting to the Gitter room with the token.
+func NewGitterNotifier(description, roomID, token string) *Notifier {
+	return newNotifier(NotifierProviderGitter, description, "room_id", roomID, "token", token)
+}
+
+func newNotifier(provider, description string, args ...string) *Notifier {
+	notifier := &Notifier{Provider: provider, Description: description, Args: make(map[string]string)}
+	for i := 0; i+1 < len(args); i += 2 {
+		notifier.Args[args[i]] = args[i+1]
+	}
+
+	return notifier
+}
+
+// Validate checks that the provider is known and all required arguments are set.
+func (n *Notifier) Validate(providers map[string]*NotifierProvider) error {
+	provider, ok := providers[n.Provider]
+	if !ok {
+		return fmt.Errorf("%w: unknown provider %q", ErrInvalidNotifier, n.Provider)
+	}
+
+	for _, arg := range provider.Required {
+		if n.Args[arg] == "" {
+			return fmt.Errorf("%w: %s provider requires %q argument", ErrInvalidNotifier, n.Provider, arg)
+		}
+	}
+
+	return nil
+}
+
+// ValidateNotifier checks the notifier against GetNotifierProviders.
+func (c *Client) ValidateNotifier(ctx context.Context, notifier *Notifier) error {
+	providers, err := c.GetNotifierProviders(ctx)
+	if err != nil {
+		return err
+	}
+
+	return notifier.Validate(providers)
+}
+
+// SubmitNotifier validates the notifier against GetNotifierProviders and creates it.
+func (c *Client) SubmitNotifier(ctx context.Context, notifier *Notifier) (bool, error) {
+	if err := c.ValidateNotifier(ctx, notifier); err != nil {
+		return false, err
+	}
+
+	return c.CreateNotifier(ctx, notifier)
+}
+
+// UpdateNotifier validates the notifier against GetNotifierProviders and updates its arguments.
+// The provider and the description can't be changed.
+func (c *Client) UpdateNotifier(ctx context.Context, notifier *Notifier) (bool, error) {
+	if err := c.ValidateNotifier(ctx, notifier); err != nil {
+		return false, err
+	}
+
+	return c.UpdateNotifierArgs(ctx, notifier.ID, notifier.Args)
+}
+
+// DiffNotifiers compares the current notifiers with the desired ones. Desired notifiers are matched
+// by ID if set, otherwise by provider and description.
+func DiffNotifiers(current, desired []*Notifier) *NotifiersDiff {
+	diff := &NotifiersDiff{
+		Create: make([]*Notifier, 0),
+		Update: make([]*Notifier, 0),
+		Delete: make([]*Notifier, 0),
+	}
+
+	matched := make(map[*Notifier]bool, len(current))
+
+	for _, d := range desired {
+		c := findNotifier(current, d)
+		if c == nil {
+			diff.Create = append(diff.Create, d)
+			continue
+		}
+
+		matched[c] = true
+
+		if !sameArgs(c.Args, d.Args) {
+			update := *d
+			update.ID = c.ID
+			diff.Update = append(diff.Update, &update)
+		}
+	}
+
+	for _, c := range current {
+		if !matched[c] && c.ID != defaultNotifierID {
+			diff.Delete = append(diff.Delete, c)
+		}
+	}
+
+	return diff
+}
+
+// Empty reports whether the notifiers are the same.
+func (d *NotifiersDiff) Empty() bool {
+	return len(d.Create) == 0 && len(d.Update) == 0 && len(d.Delete) == 0
+}
+
+func findNotifier(notifiers []*Notifier, n *Notifier) *Notifier {
+	for _, notifier := range notifiers {
+		if n.ID != "" && notifier.ID == n.ID {
+			return notifier
+		}
+
+		if n.ID == "" && notifier.Provider == n.Provider && notifier.Description == n.Description {
+			return notifier
+		}
+	}
+
+	return nil
+}
+
+func sameArgs(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+
+	return true
+}
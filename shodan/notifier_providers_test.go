@@ -0,0 +1,108 @@
+package shodan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNotifiers(t *testing.T) {
+	assert.Equal(t, &Notifier{
+		Provider:    NotifierProviderTelegram,
+		Description: "ops",
+		Args:        map[string]string{"chat_id": "42", "token": "secret"},
+	}, NewTelegramNotifier("ops", "42", "secret"))
+
+	assert.Equal(t, map[string]string{"to": "ns3777k@gmail.com"}, NewEmailNotifier("", "ns3777k@gmail.com").Args)
+	assert.Equal(t, map[string]string{"webhook_url": "https://hooks.slack.com/x"},
+		NewSlackNotifier("", "https://hooks.slack.com/x").Args)
+	assert.Equal(t, map[string]string{"url": "https://example.com/shodan"},
+		NewWebhookNotifier("", "https://example.com/shodan").Args)
+	assert.Equal(t, map[string]string{"routing_key": "key"}, NewPagerDutyNotifier("", "key").Args)
+	assert.Equal(t, map[string]string{"room_id": "room", "token": "secret"}, NewGitterNotifier("", "room", "secret").Args)
+}
+
+func TestClient_SubmitNotifier(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	created := 0
+
+	mux.HandleFunc(notifierProviderPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "notifiers/providers"))
+	})
+	mux.HandleFunc(notifierPath, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		created++
+		fmt.Fprint(w, `{"id": "GREY545DYUDYU3432", "success": true}`)
+	})
+
+	_, err := client.SubmitNotifier(context.TODO(), NewPagerDutyNotifier("ops", ""))
+	assert.True(t, errors.Is(err, ErrInvalidNotifier))
+
+	_, err = client.SubmitNotifier(context.TODO(), NewSlackNotifier("ops", "https://hooks.slack.com/x"))
+	assert.True(t, errors.Is(err, ErrInvalidNotifier))
+
+	assert.Equal(t, 0, created)
+
+	notifier := NewPagerDutyNotifier("ops", "key")
+	isOK, err := client.SubmitNotifier(context.TODO(), notifier)
+
+	assert.Nil(t, err)
+	assert.True(t, isOK)
+	assert.Equal(t, "GREY545DYUDYU3432", notifier.ID)
+	assert.Equal(t, 1, created)
+}
+
+func TestClient_UpdateNotifier(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(notifierProviderPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "notifiers/providers"))
+	})
+	mux.HandleFunc(notifierPath+"/FKDSHFKS47D", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Nil(t, r.ParseForm())
+		assert.Equal(t, "test@test.local", r.PostFormValue("to"))
+		fmt.Fprint(w, `{"success": true}`)
+	})
+
+	notifier := NewEmailNotifier("", "test@test.local")
+	notifier.ID = "FKDSHFKS47D"
+
+	isOK, err := client.UpdateNotifier(context.TODO(), notifier)
+
+	assert.Nil(t, err)
+	assert.True(t, isOK)
+}
+
+func TestDiffNotifiers(t *testing.T) {
+	current := []*Notifier{
+		{ID: "default", Provider: NotifierProviderEmail, Args: map[string]string{"to": "ns3777k@gmail.com"}},
+		{ID: "FKDSHFKS47D", Provider: NotifierProviderSlack, Description: "ops",
+			Args: map[string]string{"webhook_url": "https://hooks.slack.com/old"}},
+		{ID: "GREY545DYUDYU3432", Provider: NotifierProviderPagerDuty, Description: "legacy",
+			Args: map[string]string{"routing_key": "key"}},
+	}
+	desired := []*Notifier{
+		NewSlackNotifier("ops", "https://hooks.slack.com/new"),
+		NewWebhookNotifier("inventory", "https://example.com/shodan"),
+		{ID: "default", Provider: NotifierProviderEmail, Args: map[string]string{"to": "ns3777k@gmail.com"}},
+	}
+
+	diff := DiffNotifiers(current, desired)
+
+	assert.Equal(t, []*Notifier{desired[1]}, diff.Create)
+	assert.Len(t, diff.Update, 1)
+	assert.Equal(t, "FKDSHFKS47D", diff.Update[0].ID)
+	assert.Equal(t, "https://hooks.slack.com/new", diff.Update[0].Args["webhook_url"])
+	assert.Empty(t, desired[0].ID)
+	assert.Equal(t, []*Notifier{current[2]}, diff.Delete)
+
+	assert.True(t, DiffNotifiers(current[:1], nil).Empty())
+}
@@ -0,0 +1,393 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ns3777k/go-shodan/v4/shodan"
+)
+
+var errNotIP = errors.New("not an IP address")
+
+// UnsupportedFilterError is returned when the query uses a filter the Matcher can't evaluate offline.
+type UnsupportedFilterError struct {
+	Name string
+}
+
+// Error returns the name of the filter and the list of supported ones.
+func (e *UnsupportedFilterError) Error() string {
+	return fmt.Sprintf("query: filter %q is not supported offline, supported filters: %s",
+		e.Name, strings.Join(SupportedFilters(), ", "))
+}
+
+// InvalidValueError is returned when the filter value can't be evaluated, i.e. a malformed port or date.
+type InvalidValueError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+// Error returns the filter, the value and the reason.
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("query: invalid %s value %q: %s", e.Name, e.Value, e.Err)
+}
+
+// Unwrap returns the reason.
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// Matcher evaluates the query against banners without calling the API, i.e. over downloaded datasets.
+// Text and string filters are matched case-insensitively: text terms, org, isp, product, os, http.title,
+// http.html and ssl filters match substrings, the other ones match the whole value.
+type Matcher struct {
+	match predicate
+}
+
+type predicate func(banner *shodan.HostData) bool
+
+type valueCompiler func(value string) (predicate, error)
+
+// filterCompilers are the compilers of the supported filters by name.
+var filterCompilers = map[string]valueCompiler{
+	"port":                intFilter("port"),
+	"net":                 compileNet,
+	"ip":                  compileIP,
+	"asn":                 compileASN,
+	"country":             equalFilter(field("country")),
+	"city":                equalFilter(field("city")),
+	"org":                 containsFilter(field("org")),
+	"isp":                 containsFilter(field("isp")),
+	"product":             containsFilter(field("product")),
+	"version":             equalFilter(field("version")),
+	"os":                  containsFilter(field("os")),
+	"hostname":            compileHostname,
+	"vuln":                equalFilter(field("vuln")),
+	"tag":                 equalFilter(field("tag")),
+	"http.title":          containsFilter(field("http.title")),
+	"http.status":         intFilter("http.status"),
+	"http.html":           containsFilter(field("http.html")),
+	"ssl":                 containsFilter(sslText),
+	"ssl.cert.subject.cn": containsFilter(field("ssl.cert.subject.cn")),
+	"ssl.cert.issuer.cn":  containsFilter(field("ssl.cert.issuer.cn")),
+	"ssl.cert.expired":    compileCertExpired,
+	"ssl.version":         equalFilter(field("ssl.version")),
+	"before":              dateFilter(func(banner, date time.Time) bool { return banner.Before(date) }),
+	"after":               dateFilter(func(banner, date time.Time) bool { return !banner.Before(date) }),
+}
+
+// SupportedFilters returns the sorted names of the filters the Matcher can evaluate.
+func SupportedFilters() []string {
+	names := make([]string, 0, len(filterCompilers))
+
+	for name := range filterCompilers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Compile prepares the query for matching. *UnsupportedFilterError or *InvalidValueError is returned
+// if the query can't be evaluated offline.
+func Compile(q *Query) (*Matcher, error) {
+	match, err := compileAll(q.Nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Matcher{match: match}, nil
+}
+
+// CompileString parses and compiles the query.
+func CompileString(s string) (*Matcher, error) {
+	q, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compile(q)
+}
+
+// Match reports whether the banner matches the query.
+func (m *Matcher) Match(banner *shodan.HostData) bool {
+	return m.match(banner)
+}
+
+// Filter returns the banners matching the query.
+func (m *Matcher) Filter(banners []*shodan.HostData) []*shodan.HostData {
+	matched := make([]*shodan.HostData, 0)
+
+	for _, banner := range banners {
+		if m.match(banner) {
+			matched = append(matched, banner)
+		}
+	}
+
+	return matched
+}
+
+func compileNode(node Node) (predicate, error) {
+	switch n := node.(type) {
+	case *Term:
+		text := strings.ToLower(n.Text)
+		return negatePredicate(func(b *shodan.HostData) bool {
+			return strings.Contains(strings.ToLower(b.Data), text)
+		}, n.Negated), nil
+	case *Filter:
+		return compileFilter(n)
+	case *Group:
+		var (
+			match predicate
+			err   error
+		)
+
+		if n.Or {
+			match, err = compileAny(n.Nodes)
+		} else {
+			match, err = compileAll(n.Nodes)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return negatePredicate(match, n.Negated), nil
+	}
+
+	return nil, fmt.Errorf("query: unknown node %T", node)
+}
+
+func compileAll(nodes []Node) (predicate, error) {
+	predicates, err := compileNodes(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(b *shodan.HostData) bool {
+		for _, p := range predicates {
+			if !p(b) {
+				return false
+			}
+		}
+
+		return true
+	}, nil
+}
+
+func compileAny(nodes []Node) (predicate, error) {
+	predicates, err := compileNodes(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	return anyPredicate(predicates), nil
+}
+
+func compileNodes(nodes []Node) ([]predicate, error) {
+	predicates := make([]predicate, len(nodes))
+
+	for i, node := range nodes {
+		p, err := compileNode(node)
+		if err != nil {
+			return nil, err
+		}
+
+		predicates[i] = p
+	}
+
+	return predicates, nil
+}
+
+// compileFilter combines the values of the filter with OR.
+func compileFilter(f *Filter) (predicate, error) {
+	compile, ok := filterCompilers[strings.ToLower(f.Name)]
+	if !ok {
+		return nil, &UnsupportedFilterError{Name: f.Name}
+	}
+
+	predicates := make([]predicate, len(f.Values))
+
+	for i, value := range f.Values {
+		p, err := compile(value)
+		if err != nil {
+			return nil, &InvalidValueError{Name: f.Name, Value: value, Err: err}
+		}
+
+		predicates[i] = p
+	}
+
+	return negatePredicate(anyPredicate(predicates), f.Negated), nil
+}
+
+func anyPredicate(predicates []predicate) predicate {
+	return func(b *shodan.HostData) bool {
+		for _, p := range predicates {
+			if p(b) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func negatePredicate(p predicate, negated bool) predicate {
+	if !negated {
+		return p
+	}
+
+	return func(b *shodan.HostData) bool {
+		return !p(b)
+	}
+}
+
+// field returns the extractor of the banner field values, see shodan.HostData.FieldValues.
+func field(name string) func(b *shodan.HostData) []string {
+	return func(b *shodan.HostData) []string {
+		values, _ := b.FieldValues(name)
+		return values
+	}
+}
+
+// intFilter matches the numeric field by its decimal value.
+func intFilter(name string) valueCompiler {
+	return func(value string) (predicate, error) {
+		expected, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return equalFilter(field(name))(strconv.Itoa(expected))
+	}
+}
+
+func equalFilter(values func(b *shodan.HostData) []string) valueCompiler {
+	return func(value string) (predicate, error) {
+		return func(b *shodan.HostData) bool {
+			for _, actual := range values(b) {
+				if strings.EqualFold(actual, value) {
+					return true
+				}
+			}
+
+			return false
+		}, nil
+	}
+}
+
+func containsFilter(values func(b *shodan.HostData) []string) valueCompiler {
+	return func(value string) (predicate, error) {
+		value = strings.ToLower(value)
+
+		return func(b *shodan.HostData) bool {
+			for _, actual := range values(b) {
+				if actual != "" && strings.Contains(strings.ToLower(actual), value) {
+					return true
+				}
+			}
+
+			return false
+		}, nil
+	}
+}
+
+func dateFilter(compare func(banner, date time.Time) bool) valueCompiler {
+	return func(value string) (predicate, error) {
+		date, err := time.Parse(DateLayout, value)
+		if err != nil {
+			var isoErr error
+			if date, isoErr = time.Parse("2006-01-02", value); isoErr != nil {
+				return nil, err
+			}
+		}
+
+		return func(b *shodan.HostData) bool {
+			t, err := b.Time()
+			return err == nil && compare(t, date)
+		}, nil
+	}
+}
+
+func compileNet(value string) (predicate, error) {
+	if !strings.Contains(value, "/") {
+		return compileIP(value)
+	}
+
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(b *shodan.HostData) bool {
+		return b.IP != nil && network.Contains(b.IP)
+	}, nil
+}
+
+func compileIP(value string) (predicate, error) {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, errNotIP
+	}
+
+	return func(b *shodan.HostData) bool {
+		return ip.Equal(b.IP)
+	}, nil
+}
+
+func compileASN(value string) (predicate, error) {
+	value = strings.ToUpper(value)
+	if !strings.HasPrefix(value, "AS") {
+		value = "AS" + value
+	}
+
+	return equalFilter(field("asn"))(value)
+}
+
+func compileHostname(value string) (predicate, error) {
+	value = strings.ToLower(value)
+	hostnames := field("hostname")
+
+	return func(b *shodan.HostData) bool {
+		for _, hostname := range hostnames(b) {
+			hostname = strings.ToLower(hostname)
+			if hostname == value || strings.HasSuffix(hostname, "."+value) {
+				return true
+			}
+		}
+
+		return false
+	}, nil
+}
+
+func compileCertExpired(value string) (predicate, error) {
+	expired, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return equalFilter(field("ssl.cert.expired"))(strconv.FormatBool(expired))
+}
+
+// sslText returns the common names and organizations of the certificate subject and issuer.
+func sslText(b *shodan.HostData) []string {
+	if b.SSL == nil || b.SSL.Certificate == nil {
+		return nil
+	}
+
+	values := make([]string, 0, 4)
+
+	for _, attrs := range []*shodan.HostCertificateAttributes{b.SSL.Certificate.Subject, b.SSL.Certificate.Issuer} {
+		if attrs != nil {
+			values = append(values, attrs.CommonName, attrs.Organization)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,115 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ns3777k/go-shodan/v4/shodan"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBanner = `{
+	"ip_str": "198.20.69.74",
+	"port": 443,
+	"transport": "tcp",
+	"asn": "AS32475",
+	"org": "SingleHop LLC",
+	"isp": "SingleHop",
+	"product": "nginx",
+	"version": "1.18.0",
+	"os": "Linux 3.x",
+	"hostnames": ["www.example.com"],
+	"timestamp": "2021-03-05T10:20:30.123456",
+	"data": "HTTP/1.1 200 OK\r\nServer: nginx",
+	"location": {"country_code": "US", "city": "Chicago"},
+	"tags": ["cloud", "self-signed"],
+	"http": {"status": 200, "title": "Welcome to nginx!", "html": "<h1>Welcome</h1>"},
+	"ssl": {
+		"versions": ["TLSv1.2", "-SSLv3"],
+		"cert": {
+			"expired": true,
+			"subject": {"CN": "www.example.com", "O": "Example Inc"},
+			"issuer": {"CN": "Let's Encrypt Authority X3"}
+		}
+	},
+	"vulns": {"CVE-2021-23017": {"cvss": 7.5, "verified": false}}
+}`
+
+func newTestBanner(t *testing.T) *shodan.HostData {
+	var banner shodan.HostData
+	assert.Nil(t, json.Unmarshal([]byte(testBanner), &banner))
+
+	return &banner
+}
+
+func TestMatcher_Match(t *testing.T) {
+	banner := newTestBanner(t)
+
+	matching := []string{
+		`port:443 org:"singlehop" ssl.cert.expired:true`,
+		`port:80,443`,
+		`net:198.20.0.0/16 ip:198.20.69.74`,
+		`asn:32475 country:us city:chicago isp:single`,
+		`product:nginx version:1.18.0 os:linux`,
+		`hostname:example.com`,
+		`vuln:cve-2021-23017 tag:cloud`,
+		`http.title:welcome http.status:200 http.html:"<h1>"`,
+		`ssl:"example inc" ssl.cert.subject.cn:example.com ssl.cert.issuer.cn:"let's encrypt"`,
+		`ssl.version:tlsv1.2 -ssl.version:sslv3`,
+		`after:01/03/2021 before:2021-03-06`,
+		`"server: nginx"`,
+		`-port:22 (port:22 OR product:nginx) -(port:443 country:CN)`,
+	}
+
+	for _, q := range matching {
+		m, err := CompileString(q)
+		assert.Nil(t, err, q)
+		assert.True(t, m.Match(banner), q)
+	}
+
+	notMatching := []string{
+		`port:80`,
+		`net:10.0.0.0/8`,
+		`country:DE`,
+		`hostname:ample.com`,
+		`ssl.cert.expired:false`,
+		`before:01/03/2021`,
+		`apache`,
+		`-(port:443 product:nginx)`,
+	}
+
+	for _, q := range notMatching {
+		m, err := CompileString(q)
+		assert.Nil(t, err, q)
+		assert.False(t, m.Match(banner), q)
+	}
+}
+
+func TestMatcher_Filter(t *testing.T) {
+	banner := newTestBanner(t)
+	m, err := Compile(New(Port(443), Not(Country("CN"))))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []*shodan.HostData{banner}, m.Filter([]*shodan.HostData{banner, {Port: 22}}))
+}
+
+func TestCompile_Errors(t *testing.T) {
+	_, err := CompileString(`port:443 screenshot.label:desktop`)
+
+	var unsupported *UnsupportedFilterError
+	assert.True(t, errors.As(err, &unsupported))
+	assert.Equal(t, "screenshot.label", unsupported.Name)
+	assert.Contains(t, err.Error(), "http.title")
+
+	invalid := []string{`port:https`, `net:10.0.0.0/33`, `after:2021`, `ssl.cert.expired:maybe`, `ip:example.com`}
+	for _, q := range invalid {
+		_, err := CompileString(q)
+
+		var invalidValue *InvalidValueError
+		assert.True(t, errors.As(err, &invalidValue), q)
+	}
+
+	_, err = CompileString(`port:443 (`)
+	assert.NotNil(t, err)
+}
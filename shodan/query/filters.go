@@ -0,0 +1,138 @@
+package query
+
+import (
+	"strconv"
+	"time"
+)
+
+// DateLayout is the date format of before and after filters.
+const DateLayout = "02/01/2006"
+
+// Port matches services running on any of the ports.
+func Port(ports ...int) *Filter {
+	return NewFilter("port", ints(ports)...)
+}
+
+// Net matches hosts within any of the networks in CIDR notation.
+func Net(cidrs ...string) *Filter {
+	return NewFilter("net", cidrs...)
+}
+
+// IP matches the exact host.
+func IP(ip string) *Filter {
+	return NewFilter("ip", ip)
+}
+
+// ASN matches hosts announced by any of the autonomous systems (i.e. AS15169).
+func ASN(asn ...string) *Filter {
+	return NewFilter("asn", asn...)
+}
+
+// Org matches the organization that owns the IP space.
+func Org(org string) *Filter {
+	return NewFilter("org", org)
+}
+
+// ISP matches the ISP that manages the netblock.
+func ISP(isp string) *Filter {
+	return NewFilter("isp", isp)
+}
+
+// Country matches hosts located in any of the countries (2-letter codes).
+func Country(codes ...string) *Filter {
+	return NewFilter("country", codes...)
+}
+
+// City matches hosts located in the city.
+func City(city string) *Filter {
+	return NewFilter("city", city)
+}
+
+// Hostname matches hosts having the hostname or a subdomain of it.
+func Hostname(hostname string) *Filter {
+	return NewFilter("hostname", hostname)
+}
+
+// Product matches the name of the software that powers the service.
+func Product(product string) *Filter {
+	return NewFilter("product", product)
+}
+
+// Version matches the version of the product.
+func Version(version string) *Filter {
+	return NewFilter("version", version)
+}
+
+// OS matches the operating system.
+func OS(os string) *Filter {
+	return NewFilter("os", os)
+}
+
+// Vuln matches hosts affected by any of the CVEs.
+func Vuln(cves ...string) *Filter {
+	return NewFilter("vuln", cves...)
+}
+
+// Tag matches hosts having the tag (i.e. cloud, self-signed).
+func Tag(tag string) *Filter {
+	return NewFilter("tag", tag)
+}
+
+// HTTPTitle matches the title of the website.
+func HTTPTitle(title string) *Filter {
+	return NewFilter("http.title", title)
+}
+
+// HTTPStatus matches the response status code of the website.
+func HTTPStatus(codes ...int) *Filter {
+	return NewFilter("http.status", ints(codes)...)
+}
+
+// HTTPHTML matches the HTML of the website.
+func HTTPHTML(html string) *Filter {
+	return NewFilter("http.html", html)
+}
+
+// SSL searches all SSL data.
+func SSL(text string) *Filter {
+	return NewFilter("ssl", text)
+}
+
+// SSLCertSubjectCN matches the common name of the certificate subject.
+func SSLCertSubjectCN(cn string) *Filter {
+	return NewFilter("ssl.cert.subject.cn", cn)
+}
+
+// SSLCertIssuerCN matches the common name of the certificate issuer.
+func SSLCertIssuerCN(cn string) *Filter {
+	return NewFilter("ssl.cert.issuer.cn", cn)
+}
+
+// SSLCertExpired matches expired (or valid) certificates.
+func SSLCertExpired(expired bool) *Filter {
+	return NewFilter("ssl.cert.expired", strconv.FormatBool(expired))
+}
+
+// SSLVersion matches services supporting any of the SSL/TLS versions (i.e. tlsv1.2).
+func SSLVersion(versions ...string) *Filter {
+	return NewFilter("ssl.version", versions...)
+}
+
+// Before matches banners collected before the date.
+func Before(t time.Time) *Filter {
+	return NewFilter("before", t.Format(DateLayout))
+}
+
+// After matches banners collected after the date.
+func After(t time.Time) *Filter {
+	return NewFilter("after", t.Format(DateLayout))
+}
+
+func ints(values []int) []string {
+	strValues := make([]string, len(values))
+	for i, value := range values {
+		strValues[i] = strconv.Itoa(value)
+	}
+
+	return strValues
+}
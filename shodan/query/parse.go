@@ -0,0 +1,315 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenOpen
+	tokenClose
+	tokenOr
+	tokenAnd
+)
+
+type token struct {
+	kind    tokenKind
+	raw     string
+	negated bool
+	pos     int
+}
+
+// ParseError is returned when the query can't be parsed.
+type ParseError struct {
+	// Pos is the byte offset in the query.
+	Pos     int
+	Message string
+}
+
+// Error returns the position and the description of the problem.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("query: %s at position %d", e.Message, e.Pos)
+}
+
+// Parse parses Shodan query string. Space separated nodes are combined with AND, OR keyword combines
+// the nodes around it. Parentheses group the nodes and a leading "-" negates a term, a filter or a group.
+func Parse(s string) (*Query, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, end: len(s)}
+
+	nodes, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, &ParseError{Pos: p.tokens[p.pos].pos, Message: "unexpected closing parenthesis"}
+	}
+
+	return New(nodes...), nil
+}
+
+// MustParse is like Parse but panics if the query can't be parsed.
+func MustParse(s string) *Query {
+	q, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return q
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	end    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+// parseOr parses nodes separated by OR. A single AND sequence is returned as is.
+func (p *parser) parseOr() ([]Node, error) {
+	alternatives := make([]Node, 0)
+
+	for {
+		nodes, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		next, ok := p.peek()
+		if len(alternatives) == 0 && (!ok || next.kind != tokenOr) {
+			return nodes, nil
+		}
+
+		if len(nodes) == 1 {
+			alternatives = append(alternatives, nodes[0])
+		} else {
+			alternatives = append(alternatives, All(nodes...))
+		}
+
+		if !ok || next.kind != tokenOr {
+			return []Node{Any(alternatives...)}, nil
+		}
+
+		p.pos++
+	}
+}
+
+// parseAnd parses a sequence of nodes until OR, closing parenthesis or the end of the query.
+func (p *parser) parseAnd() ([]Node, error) {
+	nodes := make([]Node, 0)
+
+	for {
+		next, ok := p.peek()
+		if !ok || next.kind == tokenOr || next.kind == tokenClose {
+			break
+		}
+
+		p.pos++
+
+		switch next.kind {
+		case tokenAnd:
+			continue
+		case tokenOpen:
+			group, err := p.parseGroup(next)
+			if err != nil {
+				return nil, err
+			}
+
+			nodes = append(nodes, group)
+		default:
+			nodes = append(nodes, parseWord(next))
+		}
+	}
+
+	if len(nodes) == 0 {
+		pos := p.end
+		if next, ok := p.peek(); ok {
+			pos = next.pos
+		}
+
+		return nil, &ParseError{Pos: pos, Message: "expected a term or a filter"}
+	}
+
+	return nodes, nil
+}
+
+func (p *parser) parseGroup(open token) (Node, error) {
+	nodes, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if next, ok := p.peek(); !ok || next.kind != tokenClose {
+		return nil, &ParseError{Pos: open.pos, Message: "unclosed parenthesis"}
+	}
+
+	p.pos++
+
+	group, ok := nodes[0].(*Group)
+	if len(nodes) > 1 || !ok || !group.Or || group.Negated {
+		group = All(nodes...)
+	}
+
+	group.Negated = open.negated
+
+	return group, nil
+}
+
+func tokenize(s string) ([]token, error) {
+	tokens := make([]token, 0)
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpen, pos: i})
+			i++
+		case c == '-' && i+1 < len(s) && s[i+1] == '(':
+			tokens = append(tokens, token{kind: tokenOpen, negated: true, pos: i})
+			i += 2
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenClose, pos: i})
+			i++
+		default:
+			end, err := scanWord(s, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, newWordToken(s[i:end], i))
+			i = end
+		}
+	}
+
+	return tokens, nil
+}
+
+func newWordToken(raw string, pos int) token {
+	switch raw {
+	case "OR":
+		return token{kind: tokenOr, pos: pos}
+	case "AND":
+		return token{kind: tokenAnd, pos: pos}
+	}
+
+	negated := len(raw) > 1 && raw[0] == '-'
+	if negated {
+		raw = raw[1:]
+	}
+
+	return token{kind: tokenWord, raw: raw, negated: negated, pos: pos}
+}
+
+// scanWord returns the end of the word starting at i. Quoted parts may contain spaces and parentheses.
+func scanWord(s string, i int) (int, error) {
+	start := i
+	inQuote := false
+
+	for ; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case inQuote && c == '\\':
+			i++
+		case c == '"':
+			inQuote = !inQuote
+		case !inQuote && strings.IndexByte(" \t\r\n()", c) >= 0:
+			return i, nil
+		}
+	}
+
+	if inQuote {
+		return 0, &ParseError{Pos: start, Message: "unterminated quote"}
+	}
+
+	return i, nil
+}
+
+func parseWord(t token) Node {
+	if i := indexUnquoted(t.raw, ':'); i > 0 && isFilterName(t.raw[:i]) {
+		return &Filter{Name: t.raw[:i], Values: splitValues(t.raw[i+1:]), Negated: t.negated}
+	}
+
+	return &Term{Text: unquote(t.raw), Negated: t.negated}
+}
+
+func isFilterName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func indexUnquoted(s string, sep byte) int {
+	inQuote := false
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case inQuote && c == '\\':
+			i++
+		case c == '"':
+			inQuote = !inQuote
+		case !inQuote && c == sep:
+			return i
+		}
+	}
+
+	return -1
+}
+
+func splitValues(s string) []string {
+	values := make([]string, 0)
+
+	for {
+		i := indexUnquoted(s, ',')
+		if i < 0 {
+			return append(values, unquote(s))
+		}
+
+		values = append(values, unquote(s[:i]))
+		s = s[i+1:]
+	}
+}
+
+// unquote removes the quotes and unescapes quoted characters.
+func unquote(s string) string {
+	var b strings.Builder
+
+	inQuote := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case inQuote && c == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case c == '"':
+			inQuote = !inQuote
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,216 @@
+// Package query builds and parses Shodan search queries.
+//
+// A query is a list of nodes combined with AND. A node is either a free text Term, a Filter
+// (name:value1,value2) or a Group of nodes combined with AND or OR. Every node can be negated:
+//
+//	q := query.New(
+//		query.Port(80, 443),
+//		query.Org("Example Inc"),
+//		query.Not(query.Country("CN")),
+//	)
+//	q.String() // port:80,443 org:"Example Inc" -country:CN
+//
+// Queries can also be evaluated offline against banners (i.e. downloaded datasets) with Matcher:
+//
+//	m, err := query.CompileString(`port:443 ssl.cert.expired:true`)
+//	matched := m.Filter(banners)
+package query
+
+import (
+	"strings"
+)
+
+// Node is a part of the search query.
+type Node interface {
+	// String returns the node in Shodan query syntax.
+	String() string
+
+	negate() Node
+}
+
+// Term is a free text search term.
+type Term struct {
+	Text    string
+	Negated bool
+}
+
+// Filter narrows down the search by a property. Several values are combined with OR.
+type Filter struct {
+	Name    string
+	Values  []string
+	Negated bool
+}
+
+// Group is a parenthesized list of nodes combined with AND or OR.
+type Group struct {
+	Nodes   []Node
+	Or      bool
+	Negated bool
+}
+
+// Query is a list of nodes combined with AND.
+type Query struct {
+	Nodes []Node
+}
+
+// New creates a query out of the nodes.
+func New(nodes ...Node) *Query {
+	return &Query{Nodes: nodes}
+}
+
+// Text creates a free text term.
+func Text(text string) *Term {
+	return &Term{Text: text}
+}
+
+// NewFilter creates a filter with the given name and values.
+func NewFilter(name string, values ...string) *Filter {
+	return &Filter{Name: name, Values: values}
+}
+
+// All creates a group of nodes combined with AND.
+func All(nodes ...Node) *Group {
+	return &Group{Nodes: nodes}
+}
+
+// Any creates a group of nodes combined with OR.
+func Any(nodes ...Node) *Group {
+	return &Group{Nodes: nodes, Or: true}
+}
+
+// Not returns a negated copy of the node.
+func Not(node Node) Node {
+	return node.negate()
+}
+
+func (t *Term) negate() Node {
+	negated := *t
+	negated.Negated = !t.Negated
+
+	return &negated
+}
+
+// String returns the term quoted if needed.
+func (t *Term) String() string {
+	text := t.Text
+	if needsQuoting(text, ":") {
+		text = quote(text)
+	}
+
+	return negationPrefix(t.Negated) + text
+}
+
+func (f *Filter) negate() Node {
+	negated := *f
+	negated.Negated = !f.Negated
+
+	return &negated
+}
+
+// String returns the filter in name:value1,value2 form.
+func (f *Filter) String() string {
+	values := make([]string, len(f.Values))
+	for i, value := range f.Values {
+		if needsQuoting(value, ",") {
+			value = quote(value)
+		}
+
+		values[i] = value
+	}
+
+	return negationPrefix(f.Negated) + f.Name + ":" + strings.Join(values, ",")
+}
+
+func (g *Group) negate() Node {
+	negated := *g
+	negated.Negated = !g.Negated
+
+	return &negated
+}
+
+// String returns the parenthesized group.
+func (g *Group) String() string {
+	separator := " "
+	if g.Or {
+		separator = " OR "
+	}
+
+	return negationPrefix(g.Negated) + "(" + joinNodes(g.Nodes, separator) + ")"
+}
+
+// String returns the query to be passed to the search methods.
+func (q *Query) String() string {
+	return joinNodes(q.Nodes, " ")
+}
+
+// And appends the nodes to the query.
+func (q *Query) And(nodes ...Node) *Query {
+	q.Nodes = append(q.Nodes, nodes...)
+	return q
+}
+
+// Filters returns the top level filters with the given name.
+func (q *Query) Filters(name string) []*Filter {
+	filters := make([]*Filter, 0)
+
+	for _, node := range q.Nodes {
+		if filter, ok := node.(*Filter); ok && filter.Name == name {
+			filters = append(filters, filter)
+		}
+	}
+
+	return filters
+}
+
+// Remove removes the top level filters with the given name.
+func (q *Query) Remove(name string) *Query {
+	nodes := make([]Node, 0, len(q.Nodes))
+
+	for _, node := range q.Nodes {
+		if filter, ok := node.(*Filter); ok && filter.Name == name {
+			continue
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	q.Nodes = nodes
+
+	return q
+}
+
+// Set replaces the top level filters having the same name with the given filter.
+func (q *Query) Set(filter *Filter) *Query {
+	return q.Remove(filter.Name).And(filter)
+}
+
+func joinNodes(nodes []Node, separator string) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = node.String()
+	}
+
+	return strings.Join(parts, separator)
+}
+
+func negationPrefix(negated bool) string {
+	if negated {
+		return "-"
+	}
+
+	return ""
+}
+
+// needsQuoting reports whether the value must be quoted to be parsed back as a single value.
+func needsQuoting(value string, special string) bool {
+	if value == "" || value == "OR" || value == "AND" || strings.HasPrefix(value, "-") {
+		return true
+	}
+
+	return strings.ContainsAny(value, " \t\r\n\"()\\"+special)
+}
+
+func quote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,113 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_String(t *testing.T) {
+	q := New(
+		Text("default password"),
+		Port(80, 443),
+		Net("198.20.0.0/16"),
+		Org(`Example "Big" Inc`),
+		Not(Country("CN", "RU")),
+		SSLCertSubjectCN("example.com"),
+		Any(Product("nginx"), Product("Apache httpd")),
+		Not(All(HTTPStatus(404), HTTPTitle("Not Found"))),
+		After(time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC)),
+	)
+
+	expected := `"default password" port:80,443 net:198.20.0.0/16 org:"Example \"Big\" Inc" -country:CN,RU ` +
+		`ssl.cert.subject.cn:example.com (product:nginx OR product:"Apache httpd") ` +
+		`-(http.status:404 http.title:"Not Found") after:05/03/2021`
+
+	assert.Equal(t, expected, q.String())
+}
+
+func TestQuery_StringQuoting(t *testing.T) {
+	assert.Equal(t, `"a:b"`, Text("a:b").String())
+	assert.Equal(t, `"OR"`, Text("OR").String())
+	assert.Equal(t, `"-x"`, Text("-x").String())
+	assert.Equal(t, `-x`, Not(Text("x")).String())
+	assert.Equal(t, `x`, Not(Not(Text("x"))).String())
+	assert.Equal(t, `org:"a,b",c`, NewFilter("org", "a,b", "c").String())
+	assert.Equal(t, `http.title:"C:\\dir"`, HTTPTitle(`C:\dir`).String())
+	assert.Equal(t, `ssl.cert.expired:true`, SSLCertExpired(true).String())
+	assert.Equal(t, `org:""`, Org("").String())
+}
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`apache port:80,443 -country:CN org:"Example Inc" http.title:"a \"b\" (c)"`)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []Node{
+		&Term{Text: "apache"},
+		&Filter{Name: "port", Values: []string{"80", "443"}},
+		&Filter{Name: "country", Values: []string{"CN"}, Negated: true},
+		&Filter{Name: "org", Values: []string{"Example Inc"}},
+		&Filter{Name: "http.title", Values: []string{`a "b" (c)`}},
+	}, q.Nodes)
+}
+
+func TestParse_Groups(t *testing.T) {
+	q, err := Parse(`port:22 (product:OpenSSH OR product:Dropbear version:2019.78) -(country:CN country:RU)`)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []Node{
+		Port(22),
+		Any(Product("OpenSSH"), All(Product("Dropbear"), Version("2019.78"))),
+		&Group{Nodes: []Node{Country("CN"), Country("RU")}, Negated: true},
+	}, q.Nodes)
+
+	q, err = Parse(`nginx OR apache AND port:80`)
+	assert.Nil(t, err)
+	assert.Equal(t, []Node{Any(Text("nginx"), All(Text("apache"), Port(80)))}, q.Nodes)
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	queries := []string{
+		`"default password" port:80,443 -country:CN,RU`,
+		`org:"Example \"Big\" Inc" (product:nginx OR product:"Apache httpd")`,
+		`-(http.status:404 http.title:"Not Found") after:05/03/2021 "a:b"`,
+		`ssl.cert.subject.cn:example.com -ssl.cert.expired:true`,
+	}
+
+	for _, s := range queries {
+		q, err := Parse(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, s, q.String())
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	testCases := []struct {
+		query string
+		pos   int
+	}{
+		{`org:"Example`, 0},
+		{`port:80 (apache`, 8},
+		{`port:80)`, 7},
+		{`apache OR`, 9},
+		{`()`, 1},
+	}
+
+	for _, testCase := range testCases {
+		_, err := Parse(testCase.query)
+
+		parseErr, ok := err.(*ParseError)
+		assert.True(t, ok, testCase.query)
+		assert.Equal(t, testCase.pos, parseErr.Pos, testCase.query)
+	}
+}
+
+func TestQuery_Modify(t *testing.T) {
+	q := MustParse(`apache port:80 country:US port:8080`)
+
+	assert.Equal(t, []*Filter{Port(80), Port(8080)}, q.Filters("port"))
+	assert.Equal(t, `apache country:US port:443`, q.Set(Port(443)).String())
+	assert.Equal(t, `apache port:443`, q.Remove("country").String())
+	assert.Equal(t, `apache port:443 -vuln:CVE-2014-0160`, q.And(Not(Vuln("CVE-2014-0160"))).String())
+}
@@ -0,0 +1,100 @@
+package shodan
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRate is the request rate Shodan allows per API key (requests per second).
+	DefaultRate = 1.0
+
+	// DefaultBurst is the number of requests allowed to be sent at once.
+	DefaultBurst = 1
+)
+
+// RateLimiter blocks until the next request is allowed to be sent.
+// Any limiter with a compatible Wait method (i.e. golang.org/x/time/rate.Limiter) can be used.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket is a goroutine-safe token bucket rate limiter. A single bucket can be shared
+// between several clients that use the same API key.
+type TokenBucket struct {
+	m      sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a limiter that allows rate requests per second with bursts of burst requests.
+// Non-positive rate disables limiting.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// NewDefaultRateLimiter creates a limiter matching Shodan policy of 1 request per second.
+func NewDefaultRateLimiter() *TokenBucket {
+	return NewTokenBucket(DefaultRate, DefaultBurst)
+}
+
+// Wait blocks until a token is available or the context is done.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+
+	wait := b.reserve(time.Now())
+	if wait <= 0 {
+		return nil
+	}
+
+	if err := sleepContext(ctx, wait); err != nil {
+		b.cancel()
+		return err
+	}
+
+	return nil
+}
+
+// reserve takes a token (possibly going into debt) and returns how long to wait for it.
+func (b *TokenBucket) reserve(now time.Time) time.Duration {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a token reserved by a cancelled Wait.
+func (b *TokenBucket) cancel() {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.tokens++
+}
@@ -0,0 +1,79 @@
+package shodan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	bucket := NewTokenBucket(10, 2)
+	now := bucket.last
+
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, bucket.reserve(now))
+
+	// after a second the bucket is refilled up to the burst only
+	assert.Equal(t, time.Duration(0), bucket.reserve(now.Add(time.Second)))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now.Add(time.Second)))
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve(now.Add(time.Second)))
+}
+
+func TestTokenBucket_WaitContextCancelled(t *testing.T) {
+	bucket := NewTokenBucket(0.01, 1)
+	assert.Nil(t, bucket.Wait(context.TODO()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := bucket.Wait(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestTokenBucket_Unlimited(t *testing.T) {
+	bucket := NewTokenBucket(0, 1)
+
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, bucket.Wait(context.TODO()))
+	}
+}
+
+func TestClient_RateLimiterShared(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	other := NewClient(nil, testClientToken)
+	other.GeoNetBaseURL = client.GeoNetBaseURL
+
+	limiter := NewTokenBucket(20, 1)
+	client.SetRateLimiter(limiter)
+	other.SetRateLimiter(limiter)
+
+	mux.HandleFunc(infoPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "info"))
+	})
+	mux.HandleFunc(fmt.Sprintf(geonetPingPath, "127.0.0.1"), func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "geonet/ping"))
+	})
+
+	start := time.Now()
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetAPIInfo(context.TODO())
+		assert.Nil(t, err)
+
+		_, err = other.GeoPing(context.TODO(), net.ParseIP("127.0.0.1"))
+		assert.Nil(t, err)
+	}
+
+	// 4 requests with 1 token available immediately take at least 3 * 50ms
+	assert.True(t, time.Since(start) >= 150*time.Millisecond)
+}
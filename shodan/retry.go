@@ -0,0 +1,197 @@
+package shodan
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 4
+	defaultRetryMinBackoff  = time.Second
+	defaultRetryMaxBackoff  = 30 * time.Second
+)
+
+// RetryPredicate decides whether a request attempt should be repeated.
+// Exactly one of resp and err is non-nil.
+type RetryPredicate func(resp *http.Response, err error) bool
+
+// RetryPolicy describes how requests failed with transient errors are repeated.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the first retry. It's doubled for every next one.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the exponential delay.
+	MaxBackoff time.Duration
+
+	// Retryable decides which responses and errors are retried. DefaultRetryPredicate is used if nil.
+	Retryable RetryPredicate
+}
+
+// NewRetryPolicy creates retry policy with sane defaults: 4 attempts with backoff from 1 to 30 seconds
+// retrying network errors, 429 and 5xx responses.
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		MinBackoff:  defaultRetryMinBackoff,
+		MaxBackoff:  defaultRetryMaxBackoff,
+		Retryable:   DefaultRetryPredicate,
+	}
+}
+
+// DefaultRetryPredicate retries network errors, 429 Too Many Requests and 5xx responses.
+func DefaultRetryPredicate(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+func (p *RetryPolicy) retryable(resp *http.Response, err error) bool {
+	if p.Retryable == nil {
+		return DefaultRetryPredicate(resp, err)
+	}
+
+	return p.Retryable(resp, err)
+}
+
+// backoff returns the delay before the given retry (starting from 1) honoring Retry-After header.
+func (p *RetryPolicy) backoff(retry int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+
+	return jitter(exponentialBackoff(p.MinBackoff, p.MaxBackoff, retry))
+}
+
+// exponentialBackoff returns min * 2^(retry-1) capped by max.
+func exponentialBackoff(min, max time.Duration, retry int) time.Duration {
+	d := min
+	for i := 1; i < retry && d < max; i++ {
+		d *= 2
+	}
+
+	if max > 0 && d > max {
+		d = max
+	}
+
+	return d
+}
+
+// jitter randomizes the delay within [d/2, d).
+func jitter(d time.Duration) time.Duration {
+	half := int64(d / 2)
+	if half <= 0 {
+		return d
+	}
+
+	return time.Duration(half + rand.Int63n(half)) //nolint:gosec
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+
+		return d, true
+	}
+
+	return 0, false
+}
+
+// sleepContext waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// makeReplayable makes sure the request body can be sent more than once.
+func makeReplayable(req *http.Request) error {
+	if req.Body == nil || req.GetBody != nil {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	}
+
+	return nil
+}
+
+func discardResponse(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func (c *Client) doWithRetry(req *http.Request, policy *RetryPolicy) (*http.Response, error) {
+	if err := makeReplayable(req); err != nil {
+		return nil, err
+	}
+
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req.Body = body
+		}
+
+		resp, err := c.send(req)
+		if attempt >= policy.MaxAttempts || !policy.retryable(resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			discardResponse(resp)
+		}
+
+		// the response to be retried is never returned once the context is done
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if err := sleepContext(ctx, policy.backoff(attempt, resp)); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package shodan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRetryPolicy(maxAttempts int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: maxAttempts,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+	}
+}
+
+func TestClient_Do_RetryReplaysBody(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	client.SetRetryPolicy(newTestRetryPolicy(3))
+	attempts := 0
+
+	mux.HandleFunc(scanPath, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		assert.Nil(t, r.ParseForm())
+		assert.Equal(t, "82.98.86.174", r.FormValue("ips"))
+
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write(getStub(t, "scan"))
+	})
+
+	scanStatus, err := client.Scan(context.TODO(), []string{"82.98.86.174"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, "BOMA59VSGWX8QJR9", scanStatus.ID)
+}
+
+func TestClient_Do_RetryGivesUp(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	client.SetRetryPolicy(newTestRetryPolicy(2))
+	attempts := 0
+
+	mux.HandleFunc(infoPath, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error": "Rate limit reached"}`)
+	})
+
+	_, err := client.GetAPIInfo(context.TODO())
+
+	assert.True(t, errors.Is(err, ErrRateLimited))
+	assert.Equal(t, 2, attempts)
+}
+
+func TestClient_Do_RetrySkipsPermanentErrors(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	client.SetRetryPolicy(newTestRetryPolicy(5))
+	attempts := 0
+
+	mux.HandleFunc(infoPath, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	_, err := client.GetAPIInfo(context.TODO())
+
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.Equal(t, 1, attempts)
+}
+
+func TestClient_Do_RetryStopsOnContextCancel(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	client.SetRetryPolicy(newTestRetryPolicy(5))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mux.HandleFunc(infoPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+		cancel()
+	})
+
+	start := time.Now()
+	_, err := client.GetAPIInfo(ctx)
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, time.Since(start) < 10*time.Second)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{MinBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	assert.Equal(t, time.Second, exponentialBackoff(policy.MinBackoff, policy.MaxBackoff, 1))
+	assert.Equal(t, 4*time.Second, exponentialBackoff(policy.MinBackoff, policy.MaxBackoff, 3))
+	assert.Equal(t, 10*time.Second, exponentialBackoff(policy.MinBackoff, policy.MaxBackoff, 10))
+
+	d := policy.backoff(2, nil)
+	assert.True(t, d >= time.Second && d < 2*time.Second)
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	assert.Equal(t, 7*time.Second, policy.backoff(1, resp))
+}
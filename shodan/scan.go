@@ -15,6 +15,7 @@ const (
 	scanStatusPath   = "/shodan/scan/%s"
 	scanPath         = "/shodan/scan"
 	scanInternetPath = "/shodan/scan/internet"
+	scansPath        = "/shodan/scans"
 
 	// ScanStatusSubmitting is "SUBMITTING"
 	ScanStatusSubmitting ScanStatusState = "SUBMITTING"
@@ -34,6 +35,22 @@ type ScanStatus struct {
 	ID     string          `json:"id"`
 	Count  int             `json:"count"`
 	Status ScanStatusState `json:"status"`
+
+	// Created and Size are only returned by ListScans.
+	Created string `json:"created,omitempty"`
+	Size    int    `json:"size,omitempty"`
+}
+
+// ScanListOptions represents options for ListScans.
+type ScanListOptions struct {
+	// Page number to iterate over results.
+	Page int `url:"page,omitempty"`
+}
+
+// ScanList is a page of the scans submitted by the account.
+type ScanList struct {
+	Total   int           `json:"total"`
+	Matches []*ScanStatus `json:"matches"`
 }
 
 // CrawlScanStatus is the response to a scan request.
@@ -41,6 +58,12 @@ type CrawlScanStatus struct {
 	ID          string `json:"id"`
 	Count       int    `json:"count"`
 	CreditsLeft int    `json:"credits_left"`
+
+	// Submitted is a copy of the request as it was sent by SubmitScan.
+	Submitted *ScanRequest `json:"-"`
+
+	// SubmittedIPs is the encoded ips parameter sent by SubmitScan.
+	SubmittedIPs string `json:"-"`
 }
 
 // Scan requests Shodan to crawl a network.
@@ -103,3 +126,19 @@ func (c *Client) GetScanStatus(ctx context.Context, id string) (*ScanStatus, err
 
 	return &scanStatus, nil
 }
+
+// ListScans returns a page of the scans submitted by the account.
+func (c *Client) ListScans(ctx context.Context, options *ScanListOptions) (*ScanList, error) {
+	var scans ScanList
+
+	req, err := c.NewRequest("GET", scansPath, options, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Do(ctx, req, &scans); err != nil {
+		return nil, err
+	}
+
+	return &scans, nil
+}
@@ -0,0 +1,207 @@
+package shodan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	neturl "net/url"
+	"sort"
+	"strings"
+)
+
+const maxPort = 65535
+
+// ScanService is a port and protocol to be scanned.
+type ScanService struct {
+	Port     int
+	Protocol string
+}
+
+// MarshalJSON implements Marshaler interface to encode the service as [port, protocol] pair.
+func (s ScanService) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{s.Port, s.Protocol})
+}
+
+// UnmarshalJSON implements Unmarshaler interface to decode [port, protocol] pair.
+func (s *ScanService) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+
+	if len(pair) != 2 {
+		return fmt.Errorf("%w: service must be [port, protocol] pair", ErrInvalidScanRequest)
+	}
+
+	if err := json.Unmarshal(pair[0], &s.Port); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(pair[1], &s.Protocol)
+}
+
+// ScanRequest describes the networks (IPs or CIDRs) to be scanned. Every network can be scanned
+// for specific services only, in such case all networks of the request must have services.
+type ScanRequest struct {
+	Networks map[string][]ScanService
+}
+
+// NewScanRequest creates an empty scan request.
+func NewScanRequest() *ScanRequest {
+	return &ScanRequest{Networks: make(map[string][]ScanService)}
+}
+
+// Add adds the network (IP or CIDR) with the services to be scanned on it.
+func (r *ScanRequest) Add(network string, services ...ScanService) *ScanRequest {
+	if r.Networks == nil {
+		r.Networks = make(map[string][]ScanService)
+	}
+
+	r.Networks[network] = append(r.Networks[network], services...)
+
+	return r
+}
+
+// clone returns a deep copy of the request.
+func (r *ScanRequest) clone() *ScanRequest {
+	c := NewScanRequest()
+	for network, services := range r.Networks {
+		c.Networks[network] = append([]ScanService(nil), services...)
+	}
+
+	return c
+}
+
+// Targets returns the sorted list of networks.
+func (r *ScanRequest) Targets() []string {
+	targets := make([]string, 0, len(r.Networks))
+	for network := range r.Networks {
+		targets = append(targets, network)
+	}
+
+	sort.Strings(targets)
+
+	return targets
+}
+
+// Validate checks the networks and the services.
+func (r *ScanRequest) Validate() error {
+	if len(r.Networks) == 0 {
+		return fmt.Errorf("%w: no networks", ErrInvalidScanRequest)
+	}
+
+	withServices := 0
+
+	for _, network := range r.Targets() {
+		if net.ParseIP(network) == nil {
+			if _, _, err := net.ParseCIDR(network); err != nil {
+				return fmt.Errorf("%w: %s is neither IP nor CIDR", ErrInvalidScanRequest, network)
+			}
+		}
+
+		for _, service := range r.Networks[network] {
+			if service.Port < 1 || service.Port > maxPort {
+				return fmt.Errorf("%w: %s: invalid port %d", ErrInvalidScanRequest, network, service.Port)
+			}
+
+			if service.Protocol == "" {
+				return fmt.Errorf("%w: %s: empty protocol for port %d", ErrInvalidScanRequest, network, service.Port)
+			}
+		}
+
+		if len(r.Networks[network]) > 0 {
+			withServices++
+		}
+	}
+
+	if withServices > 0 && withServices < len(r.Networks) {
+		return fmt.Errorf("%w: either all or none of the networks must have services", ErrInvalidScanRequest)
+	}
+
+	return nil
+}
+
+// hasServices reports whether specific services are requested.
+func (r *ScanRequest) hasServices() bool {
+	for _, services := range r.Networks {
+		if len(services) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encode returns the value of ips parameter: comma-separated networks or
+// a JSON object mapping the networks to the services.
+func (r *ScanRequest) encode() (string, error) {
+	if !r.hasServices() {
+		return strings.Join(r.Targets(), ","), nil
+	}
+
+	b, err := json.Marshal(r.Networks)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// ValidateScanRequest validates the request and checks its protocols against GetProtocols.
+func (c *Client) ValidateScanRequest(ctx context.Context, r *ScanRequest) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	if !r.hasServices() {
+		return nil
+	}
+
+	protocols, err := c.GetProtocols(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, network := range r.Targets() {
+		for _, service := range r.Networks[network] {
+			if _, ok := protocols[service.Protocol]; !ok {
+				return fmt.Errorf("%w: %s: unknown protocol %s", ErrInvalidScanRequest, network, service.Protocol)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SubmitScan validates and submits the scan request. The request is returned in CrawlScanStatus.Submitted.
+// This method uses API scan credits: 1 IP consumes 1 scan credit.
+func (c *Client) SubmitScan(ctx context.Context, r *ScanRequest) (*CrawlScanStatus, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	ips, err := r.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	var crawlScanStatus CrawlScanStatus
+
+	body := neturl.Values{}
+	body.Add("ips", ips)
+
+	req, err := c.NewRequest("POST", scanPath, nil, strings.NewReader(body.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Do(ctx, req, &crawlScanStatus); err != nil {
+		return nil, err
+	}
+
+	crawlScanStatus.Submitted = r.clone()
+	crawlScanStatus.SubmittedIPs = ips
+
+	return &crawlScanStatus, nil
+}
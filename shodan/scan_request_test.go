@@ -0,0 +1,117 @@
+package shodan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanRequest_Validate(t *testing.T) {
+	testCases := []struct {
+		request *ScanRequest
+		valid   bool
+	}{
+		{NewScanRequest().Add("1.1.1.1").Add("198.20.0.0/24"), true},
+		{NewScanRequest().Add("1.1.1.1", ScanService{53, "dns-udp"}).Add("2001:db8::/64", ScanService{443, "https"}), true},
+		{NewScanRequest(), false},
+		{NewScanRequest().Add("example.com"), false},
+		{NewScanRequest().Add("198.20.0.0/33"), false},
+		{NewScanRequest().Add("1.1.1.1", ScanService{0, "http"}), false},
+		{NewScanRequest().Add("1.1.1.1", ScanService{65536, "http"}), false},
+		{NewScanRequest().Add("1.1.1.1", ScanService{80, ""}), false},
+		{NewScanRequest().Add("1.1.1.1", ScanService{80, "http"}).Add("8.8.8.8"), false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.request.Validate()
+		assert.Equal(t, testCase.valid, err == nil, i)
+
+		if err != nil {
+			assert.True(t, errors.Is(err, ErrInvalidScanRequest))
+		}
+	}
+}
+
+func TestScanService_JSON(t *testing.T) {
+	b, err := json.Marshal([]ScanService{{53, "dns-udp"}})
+	assert.Nil(t, err)
+	assert.Equal(t, `[[53,"dns-udp"]]`, string(b))
+
+	var services []ScanService
+	assert.Nil(t, json.Unmarshal(b, &services))
+	assert.Equal(t, []ScanService{{53, "dns-udp"}}, services)
+	assert.NotNil(t, json.Unmarshal([]byte(`[[53]]`), &services))
+}
+
+func TestClient_SubmitScan(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(scanPath, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Nil(t, r.ParseForm())
+		assert.JSONEq(t,
+			`{"1.1.1.1": [[53, "dns-udp"], [443, "https"]], "198.20.0.0/30": [[22, "ssh"]]}`,
+			r.FormValue("ips"),
+		)
+
+		w.Write(getStub(t, "scan"))
+	})
+
+	request := NewScanRequest().
+		Add("1.1.1.1", ScanService{53, "dns-udp"}, ScanService{443, "https"}).
+		Add("198.20.0.0/30", ScanService{22, "ssh"})
+
+	status, err := client.SubmitScan(context.TODO(), request)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "BOMA59VSGWX8QJR9", status.ID)
+	assert.Equal(t, request, status.Submitted)
+	assert.JSONEq(t, `{"1.1.1.1": [[53, "dns-udp"], [443, "https"]], "198.20.0.0/30": [[22, "ssh"]]}`,
+		status.SubmittedIPs)
+
+	request.Add("1.1.1.1", ScanService{80, "http"}).Add("8.8.8.8", ScanService{53, "dns-udp"})
+	request.Networks["198.20.0.0/30"][0].Port = 2222
+
+	assert.Len(t, status.Submitted.Networks, 2)
+	assert.Equal(t, []ScanService{{53, "dns-udp"}, {443, "https"}}, status.Submitted.Networks["1.1.1.1"])
+	assert.Equal(t, []ScanService{{22, "ssh"}}, status.Submitted.Networks["198.20.0.0/30"])
+}
+
+func TestClient_SubmitScanWithoutServices(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(scanPath, func(w http.ResponseWriter, r *http.Request) {
+		assert.Nil(t, r.ParseForm())
+		assert.Equal(t, "1.1.1.1,198.20.0.0/30", r.FormValue("ips"))
+		w.Write(getStub(t, "scan"))
+	})
+
+	_, err := client.SubmitScan(context.TODO(), NewScanRequest().Add("198.20.0.0/30").Add("1.1.1.1"))
+	assert.Nil(t, err)
+
+	_, err = client.SubmitScan(context.TODO(), NewScanRequest().Add("not an ip"))
+	assert.True(t, errors.Is(err, ErrInvalidScanRequest))
+}
+
+func TestClient_ValidateScanRequest(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(protocolsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "protocols"))
+	})
+
+	valid := NewScanRequest().Add("1.1.1.1", ScanService{2181, "zookeeper"})
+	assert.Nil(t, client.ValidateScanRequest(context.TODO(), valid))
+
+	invalid := NewScanRequest().Add("1.1.1.1", ScanService{80, "gopher"})
+	err := client.ValidateScanRequest(context.TODO(), invalid)
+	assert.True(t, errors.Is(err, ErrInvalidScanRequest))
+	assert.Contains(t, err.Error(), "gopher")
+}
@@ -103,3 +103,26 @@ func TestClient_GetScanStatus(t *testing.T) {
 	assert.IsType(t, scanStatusExpected, scanStatus)
 	assert.EqualValues(t, scanStatusExpected, scanStatus)
 }
+
+func TestClient_ListScans(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(scansPath, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "2", r.URL.Query().Get("page"))
+		w.Write(getStub(t, "scans"))
+	})
+
+	scans, err := client.ListScans(context.TODO(), &ScanListOptions{Page: 2})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, scans.Total)
+	assert.Len(t, scans.Matches, 2)
+	assert.EqualValues(t, &ScanStatus{
+		ID:      "BOMA59VSGWX8QJR9",
+		Status:  ScanStatusProcessing,
+		Created: "2021-01-26T08:20:14.210000",
+		Size:    2,
+	}, scans.Matches[1])
+}
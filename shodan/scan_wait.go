@@ -0,0 +1,223 @@
+package shodan
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+const defaultScanPollInterval = 10 * time.Second
+
+// ScanWaitOptions configures waiting for scans to finish.
+type ScanWaitOptions struct {
+	// PollInterval is the delay between status checks (10 seconds by default).
+	PollInterval time.Duration
+
+	// Timeout limits the whole operation. Only the context is respected if zero.
+	Timeout time.Duration
+
+	// OnStatus is called on every state transition of the scan.
+	OnStatus func(status *ScanStatus)
+}
+
+// ScanResult holds the fresh data of the scanned targets.
+type ScanResult struct {
+	// Scan is the response to the scan request.
+	Scan *CrawlScanStatus
+
+	// Status is the last status of the scan.
+	Status *ScanStatus
+
+	// SubmittedAt is the time the scan was submitted.
+	SubmittedAt time.Time
+
+	// Hosts are the scanned hosts with only the banners collected after the submission.
+	Hosts []*Host
+}
+
+func (o *ScanWaitOptions) withDefaults() ScanWaitOptions {
+	var options ScanWaitOptions
+	if o != nil {
+		options = *o
+	}
+
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaultScanPollInterval
+	}
+
+	return options
+}
+
+// WaitForScan polls the status of the scan until it's DONE.
+func (c *Client) WaitForScan(ctx context.Context, id string, options *ScanWaitOptions) (*ScanStatus, error) {
+	opts := options.withDefaults()
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	return c.waitForScan(ctx, id, "", opts)
+}
+
+func (c *Client) waitForScan(
+	ctx context.Context,
+	id string,
+	last ScanStatusState,
+	options ScanWaitOptions,
+) (*ScanStatus, error) {
+	for {
+		status, err := c.GetScanStatus(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if status.Status != last && options.OnStatus != nil {
+			options.OnStatus(status)
+		}
+
+		last = status.Status
+		if last == ScanStatusDone {
+			return status, nil
+		}
+
+		if err := sleepContext(ctx, options.PollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ResumeScans lists all scans of the account and waits for the ones that are not DONE yet.
+// The final statuses of the awaited scans are returned.
+func (c *Client) ResumeScans(ctx context.Context, options *ScanWaitOptions) ([]*ScanStatus, error) {
+	opts := options.withDefaults()
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	pending, err := c.listPendingScans(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]*ScanStatus, 0, len(pending))
+
+	for _, scan := range pending {
+		status, err := c.waitForScan(ctx, scan.ID, scan.Status, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		done = append(done, status)
+	}
+
+	return done, nil
+}
+
+func (c *Client) listPendingScans(ctx context.Context) ([]*ScanStatus, error) {
+	pending := make([]*ScanStatus, 0)
+	seen := 0
+
+	for page := 1; ; page++ {
+		scans, err := c.ListScans(ctx, &ScanListOptions{Page: page})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, scan := range scans.Matches {
+			if scan.Status != ScanStatusDone {
+				pending = append(pending, scan)
+			}
+		}
+
+		seen += len(scans.Matches)
+		if len(scans.Matches) == 0 || seen >= scans.Total {
+			return pending, nil
+		}
+	}
+}
+
+// ScanAndCollect submits the scan request, waits for the scan to finish and fetches the scanned hosts
+// keeping only the banners collected after the submission. Networks are expanded into addresses
+// and every address is looked up with GetServicesForHost, hosts without fresh banners are omitted.
+func (c *Client) ScanAndCollect(ctx context.Context, r *ScanRequest, options *ScanWaitOptions) (*ScanResult, error) {
+	opts := options.withDefaults()
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	// targets are expanded before spending scan credits so a network too large to collect fails early
+	ips := make([]net.IP, 0)
+
+	for _, network := range r.Targets() {
+		addresses, err := expandNetwork(network)
+		if err != nil {
+			return nil, err
+		}
+
+		ips = append(ips, addresses...)
+	}
+
+	result := &ScanResult{SubmittedAt: time.Now().UTC(), Hosts: make([]*Host, 0)}
+
+	scan, err := c.SubmitScan(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Scan = scan
+	submitting := &ScanStatus{ID: scan.ID, Count: scan.Count, Status: ScanStatusSubmitting}
+
+	if opts.OnStatus != nil {
+		opts.OnStatus(submitting)
+	}
+
+	if result.Status, err = c.waitForScan(ctx, scan.ID, submitting.Status, opts); err != nil {
+		return nil, err
+	}
+
+	for _, ip := range ips {
+		host, err := c.GetServicesForHost(ctx, ip.String(), nil)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if keepBannersSince(host, result.SubmittedAt) {
+			result.Hosts = append(result.Hosts, host)
+		}
+	}
+
+	return result, nil
+}
+
+// keepBannersSince removes the banners collected before the given time and reports
+// whether any banners are left.
+func keepBannersSince(host *Host, since time.Time) bool {
+	fresh := make([]*HostData, 0, len(host.Data))
+
+	for _, data := range host.Data {
+		if t, err := data.Time(); err == nil && !t.Before(since) {
+			fresh = append(fresh, data)
+		}
+	}
+
+	host.Data = fresh
+
+	return len(fresh) > 0
+}
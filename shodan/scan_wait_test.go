@@ -0,0 +1,155 @@
+package shodan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ScanAndCollect(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	statuses := []ScanStatusState{ScanStatusQueue, ScanStatusQueue, ScanStatusProcessing, ScanStatusDone}
+	polls := 0
+
+	mux.HandleFunc(scanPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "scan"))
+	})
+	mux.HandleFunc(fmt.Sprintf(scanStatusPath, "BOMA59VSGWX8QJR9"), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id": "BOMA59VSGWX8QJR9", "count": 2, "status": "%s"}`, statuses[polls])
+		polls++
+	})
+	mux.HandleFunc(hostPath+"/198.20.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fresh := time.Now().UTC().Add(time.Minute).Format(timestampLayout)
+		fmt.Fprintf(w, `{"ip_str": "198.20.0.0", "data": [
+			{"port": 22, "timestamp": "2017-09-09T14:03:08.722893"},
+			{"port": 80, "timestamp": "%s"}
+		]}`, fresh)
+	})
+	mux.HandleFunc(hostPath+"/198.20.0.1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": "No information available for that IP."}`)
+	})
+
+	transitions := make([]ScanStatusState, 0)
+	options := &ScanWaitOptions{
+		PollInterval: time.Millisecond,
+		OnStatus: func(status *ScanStatus) {
+			transitions = append(transitions, status.Status)
+		},
+	}
+
+	result, err := client.ScanAndCollect(context.TODO(), NewScanRequest().Add("198.20.0.0/31"), options)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ScanStatusState{
+		ScanStatusSubmitting, ScanStatusQueue, ScanStatusProcessing, ScanStatusDone,
+	}, transitions)
+	assert.Equal(t, "BOMA59VSGWX8QJR9", result.Scan.ID)
+	assert.Equal(t, ScanStatusDone, result.Status.Status)
+	assert.Len(t, result.Hosts, 1)
+	assert.Len(t, result.Hosts[0].Data, 1)
+	assert.Equal(t, 80, result.Hosts[0].Data[0].Port)
+}
+
+func TestClient_ScanAndCollectNetworkTooLarge(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	submitted := false
+
+	mux.HandleFunc(scanPath, func(w http.ResponseWriter, r *http.Request) {
+		submitted = true
+		w.Write(getStub(t, "scan"))
+	})
+
+	_, err := client.ScanAndCollect(context.TODO(), NewScanRequest().Add("198.20.0.0/15"), nil)
+
+	assert.True(t, errors.Is(err, ErrNetworkTooLarge))
+
+	_, err = client.ScanAndCollect(context.TODO(), NewScanRequest().Add("198.20.0.0/33"), nil)
+
+	assert.True(t, errors.Is(err, ErrInvalidScanRequest))
+	assert.False(t, submitted)
+}
+
+func TestClient_WaitForScanTimeout(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(fmt.Sprintf(scanStatusPath, "BOMA59VSGWX8QJR9"), func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "scan_status"))
+	})
+
+	options := &ScanWaitOptions{PollInterval: time.Millisecond, Timeout: 20 * time.Millisecond}
+	_, err := client.WaitForScan(context.TODO(), "BOMA59VSGWX8QJR9", options)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestClient_ResumeScans(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(scansPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getStub(t, "scans"))
+	})
+	mux.HandleFunc(fmt.Sprintf(scanStatusPath, "62TJCQSEVIN1E2TO"), func(w http.ResponseWriter, r *http.Request) {
+		t.Error("finished scan must not be polled")
+	})
+
+	polls := 0
+	mux.HandleFunc(fmt.Sprintf(scanStatusPath, "BOMA59VSGWX8QJR9"), func(w http.ResponseWriter, r *http.Request) {
+		status := ScanStatusProcessing
+		if polls > 0 {
+			status = ScanStatusDone
+		}
+
+		fmt.Fprintf(w, `{"id": "BOMA59VSGWX8QJR9", "count": 2, "status": "%s"}`, status)
+		polls++
+	})
+
+	transitions := make([]ScanStatusState, 0)
+	options := &ScanWaitOptions{
+		PollInterval: time.Millisecond,
+		OnStatus: func(status *ScanStatus) {
+			transitions = append(transitions, status.Status)
+		},
+	}
+
+	statuses, err := client.ResumeScans(context.TODO(), options)
+
+	assert.Nil(t, err)
+	assert.Len(t, statuses, 1)
+	assert.Equal(t, "BOMA59VSGWX8QJR9", statuses[0].ID)
+	assert.Equal(t, ScanStatusDone, statuses[0].Status)
+	assert.Equal(t, []ScanStatusState{ScanStatusDone}, transitions)
+}
+
+func TestExpandNetwork(t *testing.T) {
+	ips, err := expandNetwork("198.20.0.254/31")
+	assert.Nil(t, err)
+	assert.Equal(t, "198.20.0.254", ips[0].String())
+	assert.Equal(t, "198.20.0.255", ips[1].String())
+
+	ips, err = expandNetwork("10.0.0.0/22")
+	assert.Nil(t, err)
+	assert.Len(t, ips, 1024)
+	assert.Equal(t, "10.0.3.255", ips[1023].String())
+
+	ips, err = expandNetwork("2001:db8::1")
+	assert.Nil(t, err)
+	assert.Len(t, ips, 1)
+
+	_, err = expandNetwork("2001:db8::/64")
+	assert.True(t, errors.Is(err, ErrNetworkTooLarge))
+
+	_, err = expandNetwork("example.com")
+	assert.NotNil(t, err)
+}
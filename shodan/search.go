@@ -0,0 +1,141 @@
+package shodan
+
+import (
+	"context"
+	"strings"
+)
+
+// SearchIteratorOptions limits the number of results walked by SearchIterator.
+type SearchIteratorOptions struct {
+	// MaxResults stops the iteration after the given number of results (0 means no limit).
+	MaxResults int
+
+	// MaxPages stops the iteration after the given number of pages (0 means no limit).
+	MaxPages int
+}
+
+// SearchIterator walks all pages of GetHostsForQuery results one host at a time.
+type SearchIterator struct {
+	client  *Client
+	options HostQueryOptions
+	limits  SearchIteratorOptions
+
+	matches  []*HostData
+	position int
+	pages    int
+	returned int
+	total    int
+	credits  int
+	facets   map[string][]*Facet
+	done     bool
+}
+
+// NewSearchIterator creates an iterator over the search results. The iteration starts from options.Page
+// (or the first page). Facets are only requested with the first page.
+func (c *Client) NewSearchIterator(options *HostQueryOptions, limits *SearchIteratorOptions) *SearchIterator {
+	it := &SearchIterator{client: c}
+
+	if options != nil {
+		it.options = *options
+	}
+
+	if it.options.Page < 1 {
+		it.options.Page = 1
+	}
+
+	if limits != nil {
+		it.limits = *limits
+	}
+
+	return it
+}
+
+// Next returns the next host from the results. ErrIteratorDone is returned when all pages are walked
+// or one of the limits is reached.
+func (it *SearchIterator) Next(ctx context.Context) (*HostData, error) {
+	if it.limitReached() {
+		it.done = true
+	}
+
+	if !it.done && it.position >= len(it.matches) {
+		if err := it.fetch(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if it.done {
+		return nil, ErrIteratorDone
+	}
+
+	host := it.matches[it.position]
+	it.position++
+	it.returned++
+
+	return host, nil
+}
+
+// Total returns the total number of results matching the query as reported by the last page.
+func (it *SearchIterator) Total() int {
+	return it.total
+}
+
+// Facets returns the facets received with the first page.
+func (it *SearchIterator) Facets() map[string][]*Facet {
+	return it.facets
+}
+
+// Pages returns the number of fetched pages.
+func (it *SearchIterator) Pages() int {
+	return it.pages
+}
+
+// CreditsUsed returns the estimated number of query credits consumed so far: 1 credit for each page
+// past the 1st one and 1 more for the 1st page if the query contains a filter.
+func (it *SearchIterator) CreditsUsed() int {
+	return it.credits
+}
+
+func (it *SearchIterator) limitReached() bool {
+	if it.limits.MaxResults > 0 && it.returned >= it.limits.MaxResults {
+		return true
+	}
+
+	if it.position < len(it.matches) {
+		return false
+	}
+
+	if it.limits.MaxPages > 0 && it.pages >= it.limits.MaxPages {
+		return true
+	}
+
+	return it.pages > 0 && it.returned >= it.total
+}
+
+func (it *SearchIterator) fetch(ctx context.Context) error {
+	options := it.options
+	if it.pages > 0 {
+		options.Facets = ""
+	}
+
+	found, err := it.client.GetHostsForQuery(ctx, &options)
+	if err != nil {
+		return err
+	}
+
+	if options.Page > 1 || strings.Contains(options.Query, ":") {
+		it.credits++
+	}
+
+	if it.pages == 0 {
+		it.facets = found.Facets
+	}
+
+	it.pages++
+	it.options.Page++
+	it.total = found.Total
+	it.matches = found.Matches
+	it.position = 0
+	it.done = len(found.Matches) == 0
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package shodan
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveSearchPages(t *testing.T, mux *http.ServeMux, total int, pages [][]int) *[]*http.Request {
+	t.Helper()
+	requests := make([]*http.Request, 0)
+
+	mux.HandleFunc(hostSearchPath, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		found := &HostMatch{Total: total, Matches: make([]*HostData, 0)}
+		if page <= len(pages) {
+			for _, port := range pages[page-1] {
+				found.Matches = append(found.Matches, &HostData{Port: port})
+			}
+		}
+
+		if r.URL.Query().Get("facets") != "" {
+			found.Facets = map[string][]*Facet{"port": {{Count: 3, Value: "80"}}}
+		}
+
+		b, _ := json.Marshal(found)
+		w.Write(b)
+	})
+
+	return &requests
+}
+
+func collectPorts(t *testing.T, it *SearchIterator) []int {
+	t.Helper()
+	ports := make([]int, 0)
+
+	for {
+		host, err := it.Next(context.TODO())
+		if err == ErrIteratorDone {
+			return ports
+		}
+
+		assert.Nil(t, err)
+		ports = append(ports, host.Port)
+	}
+}
+
+func TestSearchIterator_AllPages(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	requests := serveSearchPages(t, mux, 5, [][]int{{1, 2}, {3, 4}, {5}})
+
+	it := client.NewSearchIterator(&HostQueryOptions{Query: "port:80", Facets: "port"}, nil)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, collectPorts(t, it))
+	assert.Len(t, *requests, 3)
+	assert.Equal(t, 3, it.Pages())
+	assert.Equal(t, 3, it.CreditsUsed())
+	assert.Equal(t, 5, it.Total())
+	assert.Equal(t, map[string][]*Facet{"port": {{Count: 3, Value: "80"}}}, it.Facets())
+	assert.Equal(t, "", (*requests)[1].URL.Query().Get("facets"))
+}
+
+func TestSearchIterator_StopsOnEmptyPage(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	requests := serveSearchPages(t, mux, 100, [][]int{{1, 2}})
+
+	it := client.NewSearchIterator(&HostQueryOptions{Query: "apache"}, nil)
+
+	assert.Equal(t, []int{1, 2}, collectPorts(t, it))
+	assert.Len(t, *requests, 2)
+	assert.Equal(t, 1, it.CreditsUsed())
+
+	_, err := it.Next(context.TODO())
+	assert.Equal(t, ErrIteratorDone, err)
+	assert.Len(t, *requests, 2)
+}
+
+func TestSearchIterator_Limits(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	requests := serveSearchPages(t, mux, 6, [][]int{{1, 2}, {3, 4}, {5, 6}})
+
+	it := client.NewSearchIterator(&HostQueryOptions{Query: "apache"}, &SearchIteratorOptions{MaxResults: 3})
+	assert.Equal(t, []int{1, 2, 3}, collectPorts(t, it))
+	assert.Len(t, *requests, 2)
+
+	it = client.NewSearchIterator(&HostQueryOptions{Query: "apache", Page: 2}, &SearchIteratorOptions{MaxPages: 1})
+	assert.Equal(t, []int{3, 4}, collectPorts(t, it))
+	assert.Len(t, *requests, 3)
+}
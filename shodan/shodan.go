@@ -36,6 +36,12 @@ type Client struct {
 	GeoNetBaseURL  string
 	Debug          bool
 	Client         *http.Client
+
+	// RetryPolicy enables retrying of transient failures. Requests are sent once if nil.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter throttles every request sent by the client. Requests are not throttled if nil.
+	RateLimiter RateLimiter
 }
 
 // NewClient creates new Shodan client
@@ -69,6 +75,23 @@ func (c *Client) SetDebug(debug bool) {
 	c.Debug = debug
 }
 
+// SetRetryPolicy sets the policy for retrying transient failures. Pass nil to disable retries.
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.RetryPolicy = policy
+}
+
+// SetRateLimiter sets the limiter every request waits for. The same limiter may be shared between
+// clients using the same API key. Pass nil to disable throttling.
+func (c *Client) SetRateLimiter(limiter RateLimiter) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.RateLimiter = limiter
+}
+
 // NewRequest prepares new request to common shodan api.
 func (c *Client) NewRequest(method string, path string, params interface{}, body io.Reader) (*http.Request, error) {
 	u, err := url.Parse(c.BaseURL + path)
@@ -123,6 +146,21 @@ func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, err
 		req = req.WithContext(ctx)
 	}
 
+	if c.RetryPolicy != nil {
+		return c.doWithRetry(req, c.RetryPolicy)
+	}
+
+	return c.send(req)
+}
+
+// send executes a single attempt of the request.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
 	if c.Debug {
 		c.dumpRequest(req)
 	}
@@ -0,0 +1,674 @@
+// Package store is an embedded store of Shodan banners.
+//
+// The store keeps the latest banner of every service (IP, port and transport) with a configurable number
+// of previous ones. Banners are appended to a newline-delimited JSON log in the store directory (readable
+// with shodan.BannerReader) and the state is rebuilt from the log on Open. Compact rewrites the log keeping
+// only the retained banners. Latest banners are indexed by port, ASN, country, org and product.
+//
+// All retained banners and the indexes are kept in memory and Open replays the entire log, so memory use
+// and startup time grow with the number of retained banners and the log size (call Compact to shrink it):
+//
+//	s, err := store.Open("/var/lib/banners", &store.Options{History: 10})
+//	go client.GetBanners(ctx, ch)
+//	err = s.Ingest(ctx, ch)
+//
+//	nginx := s.Query(&store.Query{Product: []string{"nginx"}, Country: []string{"US"}})
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ns3777k/go-shodan/v4/shodan"
+)
+
+const logFileName = "banners.json"
+
+// rename replaces the log with the compacted one, it's a variable to test the failure.
+var rename = os.Rename
+
+// ErrNoIP is returned when a banner without IP is stored.
+var ErrNoIP = errors.New("store: banner has no IP")
+
+// Index names.
+const (
+	indexPort    = "port"
+	indexASN     = "asn"
+	indexCountry = "country"
+	indexOrg     = "org"
+	indexProduct = "product"
+)
+
+// Key identifies the service.
+type Key struct {
+	IP        string
+	Port      int
+	Transport string
+}
+
+// KeyOf returns the key of the service the banner belongs to.
+func KeyOf(banner *shodan.HostData) Key {
+	key := Key{Port: banner.Port, Transport: banner.Transport}
+	if banner.IP != nil {
+		key.IP = banner.IP.String()
+	}
+
+	if key.Transport == "" {
+		key.Transport = "tcp"
+	}
+
+	return key
+}
+
+// Options configures the store.
+type Options struct {
+	// History is the number of previous banners kept per service besides the latest one.
+	History int
+}
+
+// Query selects services by their latest banners. Values of the same field are combined with OR,
+// fields are combined with AND. Empty fields match everything.
+type Query struct {
+	// IP selects services of the host.
+	IP string
+
+	Port    []int
+	ASN     []string
+	Country []string
+	Org     []string
+	Product []string
+
+	// Since and Until limit the timestamp of the latest banner.
+	Since time.Time
+	Until time.Time
+
+	// Match filters the banners after the indexed fields, i.e. with query.Matcher.Match.
+	Match func(banner *shodan.HostData) bool
+
+	// Limit caps the number of returned banners if positive.
+	Limit int
+}
+
+// Store is the embedded banner store. It's safe for concurrent use.
+type Store struct {
+	mu      sync.RWMutex
+	options Options
+	dir     string
+	file    *os.File
+	writer  *shodan.BannerWriter
+
+	services map[Key]*service
+	hosts    map[string]map[Key]struct{}
+	indexes  map[string]map[string]map[Key]struct{}
+	records  int
+}
+
+// service holds banners of the service in chronological order, the last one is the latest.
+type service struct {
+	banners []*shodan.HostData
+	times   []time.Time
+}
+
+// Open opens the store in the directory creating it if needed. The state is restored from the log,
+// undecodable lines (i.e. a line truncated by a crash) are skipped.
+func Open(dir string, options *Options) (*Store, error) {
+	s := &Store{dir: dir}
+	if options != nil {
+		s.options = *options
+	}
+
+	s.reset()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+
+	if err := s.openLog(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Put stores the banner. Banners older than the latest one of the service are kept in the history
+// if they fit into it.
+func (s *Store) Put(banner *shodan.HostData) error {
+	if banner.IP == nil {
+		return ErrNoIP
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.writer == nil {
+		return os.ErrClosed
+	}
+
+	if err := s.writer.Write(banner); err != nil {
+		return err
+	}
+
+	s.records++
+	s.apply(banner)
+
+	return nil
+}
+
+// Ingest stores the banners received from the channel (i.e. passed to GetBanners or Subscribe)
+// until it's closed or the context is done. Banners without IP are skipped.
+func (s *Store) Ingest(ctx context.Context, ch <-chan *shodan.HostData) error {
+	for {
+		select {
+		case banner, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := s.Put(banner); err != nil && !errors.Is(err, ErrNoIP) {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// IngestReader stores the banners of the reader (i.e. a dataset file) and returns the number of stored banners.
+// Undecodable lines and banners without IP are skipped.
+func (s *Store) IngestReader(r *shodan.BannerReader) (int, error) {
+	stored := 0
+
+	for {
+		banner, err := r.Next()
+		if err == io.EOF {
+			return stored, nil
+		}
+
+		var decodeErr *shodan.BannerDecodeError
+		if errors.As(err, &decodeErr) {
+			continue
+		}
+
+		if err != nil {
+			return stored, err
+		}
+
+		if err := s.Put(banner); errors.Is(err, ErrNoIP) {
+			continue
+		} else if err != nil {
+			return stored, err
+		}
+
+		stored++
+	}
+}
+
+// Get returns the latest banner of the service.
+func (s *Store) Get(key Key) (*shodan.HostData, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	svc, ok := s.services[key]
+	if !ok {
+		return nil, false
+	}
+
+	return svc.latest(), true
+}
+
+// History returns the kept banners of the service in chronological order, the last one is the latest.
+func (s *Store) History(key Key) []*shodan.HostData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	svc, ok := s.services[key]
+	if !ok {
+		return nil
+	}
+
+	return append([]*shodan.HostData(nil), svc.banners...)
+}
+
+// Host returns the latest banners of all services of the host sorted by port.
+func (s *Store) Host(ip string) []*shodan.HostData {
+	return s.Query(&Query{IP: ip})
+}
+
+// Query returns the latest banners of the matching services sorted by IP, port and transport.
+func (s *Store) Query(q *Query) []*shodan.HostData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := s.candidates(q)
+
+	sort.Slice(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+
+	banners := make([]*shodan.HostData, 0)
+
+	for _, key := range keys {
+		svc := s.services[key]
+		t := svc.times[len(svc.times)-1]
+
+		if !q.Since.IsZero() && t.Before(q.Since) || !q.Until.IsZero() && t.After(q.Until) {
+			continue
+		}
+
+		if q.Match != nil && !q.Match(svc.latest()) {
+			continue
+		}
+
+		banners = append(banners, svc.latest())
+
+		if q.Limit > 0 && len(banners) == q.Limit {
+			break
+		}
+	}
+
+	return banners
+}
+
+// Len returns the number of stored services.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.services)
+}
+
+// Garbage returns the number of log records that would be removed by Compact.
+func (s *Store) Garbage() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.records - s.retained()
+}
+
+// Compact rewrites the log keeping only the retained banners.
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.writer == nil {
+		return os.ErrClosed
+	}
+
+	tmp, err := os.Create(s.logPath() + ".tmp")
+	if err != nil {
+		return err
+	}
+
+	if err := s.writeRetained(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+
+		return err
+	}
+
+	err = s.closeLog()
+	if err == nil {
+		err = rename(tmp.Name(), s.logPath())
+	}
+
+	if err != nil {
+		// keep the original log writable, the retained banners are still in memory
+		os.Remove(tmp.Name())
+
+		if openErr := s.openLog(); openErr != nil {
+			return openErr
+		}
+
+		return err
+	}
+
+	s.records = s.retained()
+
+	return s.openLog()
+}
+
+// Close closes the log.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closeLog()
+}
+
+func (s *Store) reset() {
+	s.services = make(map[Key]*service)
+	s.hosts = make(map[string]map[Key]struct{})
+	s.indexes = map[string]map[string]map[Key]struct{}{
+		indexPort:    {},
+		indexASN:     {},
+		indexCountry: {},
+		indexOrg:     {},
+		indexProduct: {},
+	}
+}
+
+func (s *Store) logPath() string {
+	return filepath.Join(s.dir, logFileName)
+}
+
+func (s *Store) replay() error {
+	f, err := os.Open(s.logPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	r, err := shodan.NewBannerReader(f, nil)
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	for {
+		banner, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		var decodeErr *shodan.BannerDecodeError
+		if errors.As(err, &decodeErr) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		s.records++
+		s.apply(banner)
+	}
+}
+
+func (s *Store) openLog() error {
+	f, err := os.OpenFile(s.logPath(), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	// terminate a line truncated by a crash so it doesn't corrupt the next banner
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			if _, err := f.Write([]byte{'\n'}); err != nil {
+				f.Close()
+				return err
+			}
+		}
+	}
+
+	s.file = f
+	s.writer = shodan.NewBannerWriter(f, false)
+
+	return nil
+}
+
+func (s *Store) closeLog() error {
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file, s.writer = nil, nil
+
+	return err
+}
+
+func (s *Store) writeRetained(f *os.File) error {
+	w := shodan.NewBannerWriter(f, false)
+
+	keys := make([]Key, 0, len(s.services))
+	for key := range s.services {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+
+	for _, key := range keys {
+		if err := w.WriteAll(s.services[key].banners); err != nil {
+			return err
+		}
+	}
+
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+func (s *Store) retained() int {
+	retained := 0
+	for _, svc := range s.services {
+		retained += len(svc.banners)
+	}
+
+	return retained
+}
+
+// apply adds the banner to the in-memory state.
+func (s *Store) apply(banner *shodan.HostData) {
+	key := KeyOf(banner)
+
+	t, err := banner.Time()
+	if err != nil {
+		t = time.Time{}
+	}
+
+	svc, ok := s.services[key]
+	if !ok {
+		svc = &service{}
+		s.services[key] = svc
+
+		if s.hosts[key.IP] == nil {
+			s.hosts[key.IP] = make(map[Key]struct{})
+		}
+
+		s.hosts[key.IP][key] = struct{}{}
+	} else {
+		s.unindex(key, svc.latest())
+	}
+
+	svc.insert(banner, t, s.options.History+1)
+	s.index(key, svc.latest())
+}
+
+// insert keeps the banners ordered by time and drops the oldest ones over the limit.
+func (svc *service) insert(banner *shodan.HostData, t time.Time, limit int) {
+	i := sort.Search(len(svc.times), func(i int) bool {
+		return svc.times[i].After(t)
+	})
+
+	if i > 0 && svc.times[i-1].Equal(t) {
+		svc.banners[i-1] = banner
+		return
+	}
+
+	svc.banners = append(svc.banners, nil)
+	svc.times = append(svc.times, time.Time{})
+	copy(svc.banners[i+1:], svc.banners[i:])
+	copy(svc.times[i+1:], svc.times[i:])
+	svc.banners[i], svc.times[i] = banner, t
+
+	if over := len(svc.banners) - limit; over > 0 {
+		svc.banners = append([]*shodan.HostData(nil), svc.banners[over:]...)
+		svc.times = append([]time.Time(nil), svc.times[over:]...)
+	}
+}
+
+func (svc *service) latest() *shodan.HostData {
+	return svc.banners[len(svc.banners)-1]
+}
+
+func indexValues(banner *shodan.HostData) map[string]string {
+	values := map[string]string{
+		indexPort:    portValue(banner.Port),
+		indexASN:     strings.ToUpper(banner.ASN),
+		indexOrg:     strings.ToLower(banner.Organization),
+		indexProduct: strings.ToLower(banner.Product),
+	}
+
+	if banner.Location != nil {
+		values[indexCountry] = strings.ToUpper(banner.Location.CountryCode)
+	}
+
+	return values
+}
+
+func (s *Store) index(key Key, banner *shodan.HostData) {
+	for name, value := range indexValues(banner) {
+		if value == "" {
+			continue
+		}
+
+		if s.indexes[name][value] == nil {
+			s.indexes[name][value] = make(map[Key]struct{})
+		}
+
+		s.indexes[name][value][key] = struct{}{}
+	}
+}
+
+func (s *Store) unindex(key Key, banner *shodan.HostData) {
+	for name, value := range indexValues(banner) {
+		delete(s.indexes[name][value], key)
+
+		if len(s.indexes[name][value]) == 0 {
+			delete(s.indexes[name], value)
+		}
+	}
+}
+
+// candidates returns the keys matching the indexed fields of the query.
+func (s *Store) candidates(q *Query) []Key {
+	sets := make([]map[Key]struct{}, 0)
+
+	if q.IP != "" {
+		ip := q.IP
+		if parsed := net.ParseIP(ip); parsed != nil {
+			ip = parsed.String()
+		}
+
+		sets = append(sets, s.hosts[ip])
+	}
+
+	ports := make([]string, len(q.Port))
+	for i, port := range q.Port {
+		ports[i] = portValue(port)
+	}
+
+	for name, values := range map[string][]string{
+		indexPort:    ports,
+		indexASN:     upper(q.ASN),
+		indexCountry: upper(q.Country),
+		indexOrg:     lower(q.Org),
+		indexProduct: lower(q.Product),
+	} {
+		if len(values) > 0 {
+			sets = append(sets, s.union(name, values))
+		}
+	}
+
+	if len(sets) == 0 {
+		keys := make([]Key, 0, len(s.services))
+		for key := range s.services {
+			keys = append(keys, key)
+		}
+
+		return keys
+	}
+
+	sort.Slice(sets, func(i, j int) bool {
+		return len(sets[i]) < len(sets[j])
+	})
+
+	keys := make([]Key, 0, len(sets[0]))
+
+	for key := range sets[0] {
+		matched := true
+
+		for _, set := range sets[1:] {
+			if _, ok := set[key]; !ok {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func (s *Store) union(name string, values []string) map[Key]struct{} {
+	union := make(map[Key]struct{})
+
+	for _, value := range values {
+		for key := range s.indexes[name][value] {
+			union[key] = struct{}{}
+		}
+	}
+
+	return union
+}
+
+func lessKey(a, b Key) bool {
+	if a.IP != b.IP {
+		return shodan.CompareIPs(a.IP, b.IP) < 0
+	}
+
+	if a.Port != b.Port {
+		return a.Port < b.Port
+	}
+
+	return a.Transport < b.Transport
+}
+
+func portValue(port int) string {
+	return strconv.Itoa(port)
+}
+
+func upper(values []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = strings.ToUpper(value)
+	}
+
+	return result
+}
+
+func lower(values []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = strings.ToLower(value)
+	}
+
+	return result
+}
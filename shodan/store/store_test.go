@@ -0,0 +1,226 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ns3777k/go-shodan/v4/shodan"
+	"github.com/ns3777k/go-shodan/v4/shodan/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func banner(ip string, port int, product, country, timestamp string) *shodan.HostData {
+	return &shodan.HostData{
+		IP:           net.ParseIP(ip),
+		Port:         port,
+		Transport:    "tcp",
+		Product:      product,
+		ASN:          "AS15169",
+		Organization: "Google",
+		Location:     &shodan.HostLocation{CountryCode: country},
+		Timestamp:    timestamp,
+	}
+}
+
+func openStore(t *testing.T, history int) (*Store, string) {
+	dir, err := ioutil.TempDir("", "go-shodan")
+	assert.Nil(t, err)
+
+	s, err := Open(dir, &Options{History: history})
+	assert.Nil(t, err)
+
+	return s, dir
+}
+
+func TestStore_History(t *testing.T) {
+	s, dir := openStore(t, 1)
+	defer os.RemoveAll(dir)
+
+	key := Key{IP: "8.8.8.8", Port: 53, Transport: "tcp"}
+
+	assert.Nil(t, s.Put(banner("8.8.8.8", 53, "dnsmasq", "US", "2020-01-02T00:00:00.000000")))
+	assert.Nil(t, s.Put(banner("8.8.8.8", 53, "bind", "US", "2020-01-03T00:00:00.000000")))
+	assert.Nil(t, s.Put(banner("8.8.8.8", 53, "unbound", "US", "2020-01-01T00:00:00.000000")))
+	assert.Equal(t, ErrNoIP, s.Put(&shodan.HostData{Port: 80}))
+
+	latest, ok := s.Get(key)
+	assert.True(t, ok)
+	assert.Equal(t, "bind", latest.Product)
+
+	history := s.History(key)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "dnsmasq", history[0].Product)
+	assert.Equal(t, "bind", history[1].Product)
+
+	_, ok = s.Get(Key{IP: "8.8.4.4", Port: 53, Transport: "tcp"})
+	assert.False(t, ok)
+	assert.Nil(t, s.History(Key{IP: "8.8.4.4", Port: 53, Transport: "tcp"}))
+
+	assert.Empty(t, s.Query(&Query{Product: []string{"dnsmasq"}}))
+	assert.Len(t, s.Query(&Query{Product: []string{"BIND"}}), 1)
+	assert.Nil(t, s.Close())
+}
+
+func TestStore_Query(t *testing.T) {
+	s, dir := openStore(t, 0)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, s.Put(banner("8.8.8.8", 443, "nginx", "US", "2020-01-02T00:00:00.000000")))
+	assert.Nil(t, s.Put(banner("8.8.8.8", 80, "nginx", "US", "2020-01-03T00:00:00.000000")))
+	assert.Nil(t, s.Put(banner("1.1.1.1", 80, "Apache httpd", "AU", "2020-01-04T00:00:00.000000")))
+	assert.Nil(t, s.Put(banner("10.0.0.1", 22, "OpenSSH", "de", "2020-01-05T00:00:00.000000")))
+	assert.Equal(t, 4, s.Len())
+
+	ports := func(banners []*shodan.HostData) []int {
+		result := make([]int, len(banners))
+		for i, b := range banners {
+			result[i] = b.Port
+		}
+
+		return result
+	}
+
+	assert.Equal(t, []int{80, 443}, ports(s.Host("8.8.8.8")))
+	assert.Empty(t, s.Host("9.9.9.9"))
+	assert.Len(t, s.Query(&Query{}), 4)
+	assert.Equal(t, []int{80, 80}, ports(s.Query(&Query{Port: []int{80}})))
+	assert.Equal(t, []int{80, 80, 443}, ports(s.Query(&Query{Port: []int{80, 443}, Country: []string{"us", "AU"}})))
+	assert.Equal(t, []int{22}, ports(s.Query(&Query{Country: []string{"DE"}, ASN: []string{"as15169"}})))
+	assert.Equal(t, []int{80, 443}, ports(s.Query(&Query{Org: []string{"google"}, Product: []string{"nginx"}})))
+	assert.Empty(t, s.Query(&Query{Port: []int{22}, Product: []string{"nginx"}}))
+	assert.Len(t, s.Query(&Query{Limit: 2}), 2)
+
+	since := mustTime(t, "2020-01-03T00:00:00.000000")
+	assert.Len(t, s.Query(&Query{Since: since, Until: since.Add(24 * time.Hour)}), 2)
+
+	m, err := query.CompileString("net:8.0.0.0/8 port:443")
+	assert.Nil(t, err)
+
+	matched := s.Query(&Query{Match: m.Match})
+	assert.Len(t, matched, 1)
+	assert.Equal(t, 443, matched[0].Port)
+	assert.Nil(t, s.Close())
+}
+
+func TestStore_Reopen(t *testing.T) {
+	s, dir := openStore(t, 1)
+	defer os.RemoveAll(dir)
+
+	for _, timestamp := range []string{"2020-01-01", "2020-01-02", "2020-01-03"} {
+		assert.Nil(t, s.Put(banner("8.8.8.8", 53, "bind-"+timestamp, "US", timestamp+"T00:00:00.000000")))
+	}
+
+	assert.Nil(t, s.Put(banner("1.1.1.1", 53, "unbound", "AU", "2020-01-01T00:00:00.000000")))
+	assert.Equal(t, 1, s.Garbage())
+	assert.Nil(t, s.Close())
+	assert.Equal(t, os.ErrClosed, s.Put(banner("1.1.1.1", 53, "unbound", "AU", "")))
+
+	// a line truncated by a crash is skipped
+	f, err := os.OpenFile(filepath.Join(dir, logFileName), os.O_WRONLY|os.O_APPEND, 0o644)
+	assert.Nil(t, err)
+	_, err = f.WriteString(`{"ip_str": "9.9.9.9", "port": 5`)
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+
+	s, err = Open(dir, &Options{History: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, s.Len())
+	assert.Len(t, s.History(Key{IP: "8.8.8.8", Port: 53, Transport: "tcp"}), 2)
+	assert.Nil(t, s.Put(banner("9.9.9.9", 53, "pdns", "CH", "2020-01-01T00:00:00.000000")))
+
+	assert.Nil(t, s.Compact())
+	assert.Equal(t, 0, s.Garbage())
+	assert.Nil(t, s.Close())
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, logFileName))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, strings.Count(string(data), "\n"))
+
+	s, err = Open(dir, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, s.Len())
+
+	latest, ok := s.Get(Key{IP: "8.8.8.8", Port: 53, Transport: "tcp"})
+	assert.True(t, ok)
+	assert.Equal(t, "bind-2020-01-03", latest.Product)
+	assert.Nil(t, s.Close())
+}
+
+func TestStore_CompactFailure(t *testing.T) {
+	s, dir := openStore(t, 0)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, s.Put(banner("8.8.8.8", 53, "dnsmasq", "US", "2020-01-01T00:00:00.000000")))
+	assert.Nil(t, s.Put(banner("8.8.8.8", 53, "bind", "US", "2020-01-02T00:00:00.000000")))
+
+	renameErr := errors.New("rename failed")
+	rename = func(string, string) error { return renameErr }
+
+	defer func() { rename = os.Rename }()
+
+	assert.Equal(t, renameErr, s.Compact())
+	assert.Equal(t, 1, s.Garbage())
+
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+
+	// the original log is still written
+	assert.Nil(t, s.Put(banner("1.1.1.1", 53, "unbound", "AU", "2020-01-01T00:00:00.000000")))
+	assert.Nil(t, s.Close())
+
+	s, err = Open(dir, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, s.Len())
+	assert.Nil(t, s.Close())
+}
+
+func TestStore_Ingest(t *testing.T) {
+	s, dir := openStore(t, 0)
+	defer os.RemoveAll(dir)
+
+	ch := make(chan *shodan.HostData, 3)
+	ch <- banner("8.8.8.8", 53, "bind", "US", "2020-01-01T00:00:00.000000")
+	ch <- &shodan.HostData{Port: 80}
+	ch <- banner("8.8.4.4", 53, "bind", "US", "2020-01-01T00:00:00.000000")
+	close(ch)
+
+	assert.Nil(t, s.Ingest(context.Background(), ch))
+	assert.Equal(t, 2, s.Len())
+
+	input := `{"ip_str": "1.1.1.1", "port": 80, "timestamp": "2020-01-01T00:00:00.000000"}
+{"broken
+{"port": 80}
+{"ip_str": "1.0.0.1", "port": 80, "transport": "udp"}
+`
+
+	r, err := shodan.NewBannerReader(strings.NewReader(input), nil)
+	assert.Nil(t, err)
+
+	stored, err := s.IngestReader(r)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, stored)
+	assert.Equal(t, 4, s.Len())
+
+	_, ok := s.Get(Key{IP: "1.0.0.1", Port: 80, Transport: "udp"})
+	assert.True(t, ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, s.Ingest(ctx, make(chan *shodan.HostData)))
+	assert.Nil(t, s.Close())
+}
+
+func mustTime(t *testing.T, timestamp string) time.Time {
+	result, err := (&shodan.HostData{Timestamp: timestamp}).Time()
+	assert.Nil(t, err)
+
+	return result
+}
@@ -67,7 +67,7 @@ func (c *Client) DoStream(ctx context.Context, req *http.Request) (*http.Respons
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
+		defer resp.Body.Close()
 		return nil, getErrorFromResponse(resp)
 	}
 
@@ -89,91 +89,89 @@ func (c *Client) startStreaming(ctx context.Context, path string) (*http.Respons
 	return resp, nil
 }
 
-// GetBannersByASN provides a filtered, bandwidth-saving view of the Banners stream in case
-// you are only interested in devices located in certain ASNs.
-func (c *Client) GetBannersByASN(ctx context.Context, asn []string, ch chan *HostData) error {
-	path := fmt.Sprintf(bannersASNPath, strings.Join(asn, ","))
-	resp, err := c.startStreaming(ctx, path)
-	if err != nil {
-		return err
-	}
+// StreamSource is the path of a streaming endpoint.
+type StreamSource string
 
-	go c.handleResponseStream(resp, ch)
+// BannersSource is the stream of ALL banners.
+func BannersSource() StreamSource {
+	return bannersPath
+}
 
-	return nil
+// ASNSource is the stream of banners for devices located in the given ASNs.
+func ASNSource(asn []string) StreamSource {
+	return StreamSource(fmt.Sprintf(bannersASNPath, strings.Join(asn, ",")))
 }
 
-// GetBannersByCountries provides a filtered, bandwidth-saving view of the Banners
-// stream in case you are only interested in devices located in certain countries.
-func (c *Client) GetBannersByCountries(ctx context.Context, countries []string, ch chan *HostData) error {
+// CountriesSource is the stream of banners for devices located in the given countries.
+func CountriesSource(countries []string) StreamSource {
 	strCountries := make([]string, 0)
 	for _, country := range countries {
 		strCountries = append(strCountries, strings.ToUpper(country))
 	}
 
-	path := fmt.Sprintf(bannersCountryPath, strings.Join(strCountries, ","))
-	resp, err := c.startStreaming(ctx, path)
-	if err != nil {
-		return err
-	}
-
-	go c.handleResponseStream(resp, ch)
-
-	return nil
+	return StreamSource(fmt.Sprintf(bannersCountryPath, strings.Join(strCountries, ",")))
 }
 
-// GetBannersByPorts returns only banner data for the list of specified hosts.
-// This stream provides a filtered, bandwidth-saving view of the Banners stream
-// in case you are only interested in a specific list of ports.
-func (c *Client) GetBannersByPorts(ctx context.Context, ports []int, ch chan *HostData) error {
+// PortsSource is the stream of banners for the given ports.
+func PortsSource(ports []int) StreamSource {
 	strPorts := make([]string, 0)
 	for _, port := range ports {
 		strPorts = append(strPorts, strconv.Itoa(port))
 	}
 
-	path := fmt.Sprintf(bannersPortsPath, strings.Join(strPorts, ","))
-	resp, err := c.startStreaming(ctx, path)
-	if err != nil {
-		return err
-	}
+	return StreamSource(fmt.Sprintf(bannersPortsPath, strings.Join(strPorts, ",")))
+}
 
-	go c.handleResponseStream(resp, ch)
+// AlertSource is the stream of banners discovered on the IP range of the network alert.
+func AlertSource(id string) StreamSource {
+	return StreamSource(fmt.Sprintf(bannersAlertPath, id))
+}
 
-	return nil
+// AlertsSource is the stream of banners discovered on all IP ranges of the network alerts.
+func AlertsSource() StreamSource {
+	return bannersAlertsPath
+}
+
+// GetBannersByASN provides a filtered, bandwidth-saving view of the Banners stream in case
+// you are only interested in devices located in certain ASNs.
+func (c *Client) GetBannersByASN(ctx context.Context, asn []string, ch chan *HostData) error {
+	return c.getBanners(ctx, ASNSource(asn), ch)
+}
+
+// GetBannersByCountries provides a filtered, bandwidth-saving view of the Banners
+// stream in case you are only interested in devices located in certain countries.
+func (c *Client) GetBannersByCountries(ctx context.Context, countries []string, ch chan *HostData) error {
+	return c.getBanners(ctx, CountriesSource(countries), ch)
+}
+
+// GetBannersByPorts returns only banner data for the list of specified hosts.
+// This stream provides a filtered, bandwidth-saving view of the Banners stream
+// in case you are only interested in a specific list of ports.
+func (c *Client) GetBannersByPorts(ctx context.Context, ports []int, ch chan *HostData) error {
+	return c.getBanners(ctx, PortsSource(ports), ch)
 }
 
 // GetBannersByAlert subscribes to banners discovered on the IP range defined
 // in a specific network alert.
 func (c *Client) GetBannersByAlert(ctx context.Context, id string, ch chan *HostData) error {
-	path := fmt.Sprintf(bannersAlertPath, id)
-	resp, err := c.startStreaming(ctx, path)
-	if err != nil {
-		return err
-	}
-
-	go c.handleResponseStream(resp, ch)
-
-	return nil
+	return c.getBanners(ctx, AlertSource(id), ch)
 }
 
 // GetBannersByAlerts subscribes to banners discovered on all IP ranges described
 // in the network alerts.
 func (c *Client) GetBannersByAlerts(ctx context.Context, ch chan *HostData) error {
-	resp, err := c.startStreaming(ctx, bannersAlertsPath)
-	if err != nil {
-		return err
-	}
-
-	go c.handleResponseStream(resp, ch)
-
-	return nil
+	return c.getBanners(ctx, AlertsSource(), ch)
 }
 
 // GetBanners provides ALL of the data that Shodan collects. Use this stream
 // if you need access to everything and / or want to store your own Shodan database
 // locally. If you only care about specific ports, please use the Ports stream.
 func (c *Client) GetBanners(ctx context.Context, ch chan *HostData) error {
-	resp, err := c.startStreaming(ctx, bannersPath)
+	return c.getBanners(ctx, BannersSource(), ch)
+}
+
+func (c *Client) getBanners(ctx context.Context, source StreamSource, ch chan *HostData) error {
+	resp, err := c.startStreaming(ctx, string(source))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,262 @@
+package shodan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultStreamMinBackoff = time.Second
+	defaultStreamMaxBackoff = time.Minute
+)
+
+// BannerDecodeError is returned when a line can't be decoded into HostData.
+type BannerDecodeError struct {
+	// Line is the 1-based line number within the stream connection or the file.
+	Line int
+
+	// Data is the undecodable line.
+	Data []byte
+
+	// Err is the decoding error.
+	Err error
+}
+
+// Error returns the line number and the decoding error.
+func (e *BannerDecodeError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// Unwrap returns the decoding error.
+func (e *BannerDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// StreamOptions configures Subscribe.
+type StreamOptions struct {
+	// MinBackoff is the delay before the first reconnect attempt. It's doubled for every next one.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between reconnect attempts.
+	MaxBackoff time.Duration
+
+	// MaxReconnects is the number of consecutive failed reconnect attempts before giving up (0 means no limit).
+	MaxReconnects int
+
+	// OnError is called for every disconnect, failed reconnect and *BannerDecodeError.
+	// It's called from the streaming goroutine so it must not block for long.
+	OnError func(err error)
+}
+
+// StreamStats holds counters of the subscription.
+type StreamStats struct {
+	Reconnects     int64
+	Banners        int64
+	DecodeFailures int64
+}
+
+// Subscription is a reconnecting stream of banners created by Subscribe.
+type Subscription struct {
+	client  *Client
+	source  StreamSource
+	ch      chan *HostData
+	options StreamOptions
+
+	reconnects     int64
+	banners        int64
+	decodeFailures int64
+
+	done chan struct{}
+	err  error
+}
+
+// Subscribe connects to the streaming endpoint and sends banners to ch. Unlike GetBanners and friends,
+// the subscription reconnects with backoff after disconnects and skips undecodable lines reporting them
+// to options.OnError. Only the first connection error is returned, ch is closed once the subscription ends
+// (the context is done, a permanent error occurs or MaxReconnects is exceeded).
+func (c *Client) Subscribe(
+	ctx context.Context,
+	source StreamSource,
+	ch chan *HostData,
+	options *StreamOptions,
+) (*Subscription, error) {
+	s := &Subscription{
+		client: c,
+		source: source,
+		ch:     ch,
+		done:   make(chan struct{}),
+	}
+
+	if options != nil {
+		s.options = *options
+	}
+
+	if s.options.MinBackoff <= 0 {
+		s.options.MinBackoff = defaultStreamMinBackoff
+	}
+
+	if s.options.MaxBackoff <= 0 {
+		s.options.MaxBackoff = defaultStreamMaxBackoff
+	}
+
+	resp, err := c.startStreaming(ctx, string(source))
+	if err != nil {
+		return nil, err
+	}
+
+	go s.run(ctx, resp)
+
+	return s, nil
+}
+
+// Stats returns current subscription counters.
+func (s *Subscription) Stats() StreamStats {
+	return StreamStats{
+		Reconnects:     atomic.LoadInt64(&s.reconnects),
+		Banners:        atomic.LoadInt64(&s.banners),
+		DecodeFailures: atomic.LoadInt64(&s.decodeFailures),
+	}
+}
+
+// Done is closed when the subscription ends.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the reason the subscription ended. It returns nil until Done is closed.
+func (s *Subscription) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+func (s *Subscription) report(err error) {
+	if s.options.OnError != nil {
+		s.options.OnError(err)
+	}
+}
+
+func (s *Subscription) run(ctx context.Context, resp *http.Response) {
+	defer close(s.done)
+	defer close(s.ch)
+
+	for {
+		err := s.consume(ctx, resp)
+		resp.Body.Close()
+
+		if ctx.Err() != nil {
+			s.err = ctx.Err()
+			return
+		}
+
+		s.report(err)
+
+		resp, err = s.reconnect(ctx)
+		if err != nil {
+			s.err = err
+			return
+		}
+	}
+}
+
+// consume reads banners until the connection breaks and returns the reason.
+func (s *Subscription) consume(ctx context.Context, resp *http.Response) error {
+	reader := bufio.NewReader(resp.Body)
+	line := 0
+
+	for {
+		chunk, err := reader.ReadBytes('\n')
+		if err != nil && len(bytes.TrimSpace(chunk)) == 0 {
+			return disconnectError(err)
+		}
+
+		line++
+		chunk = bytes.TrimRight(chunk, "\n\r")
+
+		if len(chunk) > 0 {
+			if sendErr := s.send(ctx, line, chunk); sendErr != nil {
+				return sendErr
+			}
+		}
+
+		if err != nil {
+			return disconnectError(err)
+		}
+	}
+}
+
+func (s *Subscription) send(ctx context.Context, line int, chunk []byte) error {
+	banner := new(HostData)
+	if err := json.Unmarshal(chunk, banner); err != nil {
+		atomic.AddInt64(&s.decodeFailures, 1)
+		s.report(&BannerDecodeError{Line: line, Data: chunk, Err: err})
+
+		return nil
+	}
+
+	select {
+	case s.ch <- banner:
+		atomic.AddInt64(&s.banners, 1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Subscription) reconnect(ctx context.Context) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		delay := jitter(exponentialBackoff(s.options.MinBackoff, s.options.MaxBackoff, attempt))
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+
+		resp, err := s.client.startStreaming(ctx, string(s.source))
+		if err == nil {
+			atomic.AddInt64(&s.reconnects, 1)
+			return resp, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		s.report(err)
+
+		if !isTransientStreamError(err) {
+			return nil, err
+		}
+
+		if s.options.MaxReconnects > 0 && attempt >= s.options.MaxReconnects {
+			return nil, err
+		}
+	}
+}
+
+func disconnectError(err error) error {
+	if errors.Is(err, io.EOF) {
+		return ErrStreamDisconnected
+	}
+
+	return fmt.Errorf("%w: %s", ErrStreamDisconnected, err)
+}
+
+// isTransientStreamError reports whether reconnecting may help.
+func isTransientStreamError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServerError)
+}
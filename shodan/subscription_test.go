@@ -0,0 +1,115 @@
+package shodan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_SubscribeReconnects(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	connections := 0
+	mux.HandleFunc(string(PortsSource([]int{22, 80})), func(w http.ResponseWriter, r *http.Request) {
+		connections++
+
+		switch connections {
+		case 1:
+			fmt.Fprint(w, "{\"port\": 22}\n{\"port\": \n\n{\"port\": 80}\n")
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, "{\"port\": 8080}")
+		}
+	})
+
+	var m sync.Mutex
+	reported := make([]error, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan *HostData)
+	subscription, err := client.Subscribe(ctx, PortsSource([]int{22, 80}), ch, &StreamOptions{
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond,
+		OnError: func(err error) {
+			m.Lock()
+			defer m.Unlock()
+			reported = append(reported, err)
+		},
+	})
+	assert.Nil(t, err)
+
+	ports := make([]int, 0)
+	for banner := range ch {
+		ports = append(ports, banner.Port)
+		if len(ports) == 3 {
+			cancel()
+		}
+	}
+
+	<-subscription.Done()
+
+	assert.Equal(t, []int{22, 80, 8080}, ports)
+	assert.True(t, errors.Is(subscription.Err(), context.Canceled))
+	assert.Equal(t, StreamStats{Reconnects: 1, Banners: 3, DecodeFailures: 1}, subscription.Stats())
+
+	m.Lock()
+	defer m.Unlock()
+
+	var decodeErr *BannerDecodeError
+	assert.True(t, errors.As(reported[0], &decodeErr))
+	assert.Equal(t, 2, decodeErr.Line)
+	assert.True(t, errors.Is(reported[1], ErrStreamDisconnected))
+	assert.True(t, errors.Is(reported[2], ErrServerError))
+}
+
+func TestClient_SubscribeStopsOnPermanentError(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	connections := 0
+	mux.HandleFunc(bannersPath, func(w http.ResponseWriter, r *http.Request) {
+		connections++
+		if connections > 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		fmt.Fprint(w, "{\"port\": 22}\n")
+	})
+
+	ch := make(chan *HostData, 10)
+	subscription, err := client.Subscribe(context.TODO(), BannersSource(), ch, &StreamOptions{
+		MinBackoff: time.Millisecond,
+	})
+	assert.Nil(t, err)
+
+	<-subscription.Done()
+
+	assert.True(t, errors.Is(subscription.Err(), ErrUnauthorized))
+	assert.Equal(t, 2, connections)
+	assert.Len(t, ch, 1)
+}
+
+func TestClient_SubscribeInitialError(t *testing.T) {
+	mux, tearDownTestServe, client := setUpTestServe()
+	defer tearDownTestServe()
+
+	mux.HandleFunc(bannersPath, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	subscription, err := client.Subscribe(context.TODO(), BannersSource(), make(chan *HostData), nil)
+
+	assert.Nil(t, subscription)
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+}
@@ -0,0 +1,83 @@
+package shodan
+
+// HostVulnerability describes a vulnerability the service is affected by.
+type HostVulnerability struct {
+	CVSS       float64  `json:"cvss"`
+	Summary    string   `json:"summary"`
+	References []string `json:"references"`
+
+	// Verified is true if the vulnerability was confirmed by Shodan, otherwise
+	// it's implied by the product version.
+	Verified bool `json:"verified"`
+}
+
+// AllVulns returns the vulnerabilities of all services by CVE id. If the same CVE is reported
+// by several services the verified one is preferred.
+func (h *Host) AllVulns() map[string]*HostVulnerability {
+	vulns := make(map[string]*HostVulnerability)
+
+	for _, data := range h.Data {
+		for cve, vuln := range data.Vulns {
+			if vuln == nil {
+				continue
+			}
+
+			if known, ok := vulns[cve]; !ok || (!known.Verified && vuln.Verified) {
+				vulns[cve] = vuln
+			}
+		}
+	}
+
+	return vulns
+}
+
+// VerifiedVulns returns only the vulnerabilities confirmed by Shodan.
+func (h *Host) VerifiedVulns() map[string]*HostVulnerability {
+	vulns := make(map[string]*HostVulnerability)
+
+	for cve, vuln := range h.AllVulns() {
+		if vuln.Verified {
+			vulns[cve] = vuln
+		}
+	}
+
+	return vulns
+}
+
+// VulnsByPort groups the vulnerabilities by the port of the affected service.
+func (h *Host) VulnsByPort() map[int]map[string]*HostVulnerability {
+	vulns := make(map[int]map[string]*HostVulnerability)
+
+	for _, data := range h.Data {
+		for cve, vuln := range data.Vulns {
+			if vuln == nil {
+				continue
+			}
+
+			if _, ok := vulns[data.Port]; !ok {
+				vulns[data.Port] = make(map[string]*HostVulnerability)
+			}
+
+			vulns[data.Port][cve] = vuln
+		}
+	}
+
+	return vulns
+}
+
+// HighestCVSS returns the CVE id and the score of the most severe vulnerability.
+// An empty id is returned if the host has no known vulnerabilities.
+func (h *Host) HighestCVSS() (string, float64) {
+	var (
+		highestCVE  string
+		highestCVSS float64
+	)
+
+	for cve, vuln := range h.AllVulns() {
+		if highestCVE == "" || vuln.CVSS > highestCVSS || (vuln.CVSS == highestCVSS && cve < highestCVE) {
+			highestCVE, highestCVSS = cve, vuln.CVSS
+		}
+	}
+
+	return highestCVE, highestCVSS
+}
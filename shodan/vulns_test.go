@@ -0,0 +1,56 @@
+package shodan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getVulnerableHost(t *testing.T) *Host {
+	t.Helper()
+
+	var host Host
+	assert.Nil(t, json.Unmarshal(getStub(t, "host/vulns"), &host))
+
+	return &host
+}
+
+func TestHost_AllVulns(t *testing.T) {
+	host := getVulnerableHost(t)
+	vulns := host.AllVulns()
+
+	assert.Len(t, vulns, 3)
+	assert.True(t, vulns["CVE-2019-0211"].Verified)
+	assert.Equal(t, []string{"https://example.com"}, vulns["CVE-2017-7679"].References)
+}
+
+func TestHost_VerifiedVulns(t *testing.T) {
+	host := getVulnerableHost(t)
+	vulns := host.VerifiedVulns()
+
+	assert.Len(t, vulns, 2)
+	assert.Contains(t, vulns, "CVE-2019-0211")
+	assert.Contains(t, vulns, "CVE-2014-0160")
+}
+
+func TestHost_VulnsByPort(t *testing.T) {
+	host := getVulnerableHost(t)
+	vulns := host.VulnsByPort()
+
+	assert.Len(t, vulns, 2)
+	assert.Len(t, vulns[80], 2)
+	assert.Len(t, vulns[443], 2)
+	assert.Equal(t, "Heartbleed", vulns[443]["CVE-2014-0160"].Summary)
+}
+
+func TestHost_HighestCVSS(t *testing.T) {
+	cve, cvss := getVulnerableHost(t).HighestCVSS()
+
+	assert.Equal(t, "CVE-2017-7679", cve)
+	assert.Equal(t, 7.5, cvss)
+
+	cve, cvss = (&Host{}).HighestCVSS()
+	assert.Equal(t, "", cve)
+	assert.Equal(t, 0.0, cvss)
+}